@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/rosszurowski/small-seasons-bot/bsky"
+	bskypost "github.com/rosszurowski/small-seasons-bot/bsky/post"
+	"github.com/rosszurowski/small-seasons-bot/mastodon"
+	"github.com/rosszurowski/small-seasons-bot/seasons"
+)
+
+// account is where a platform's posts go: its account on the platform, or the
+// output of a simulation.
+type account interface {
+	// since returns the account's posts made since from.
+	since(ctx context.Context, from time.Time) ([]recentPost, error)
+	// publish makes a post, and returns it with the ID it was given, or
+	// without one if nothing was posted.
+	publish(ctx context.Context, d draft) (recentPost, error)
+}
+
+// draft is a post to be made, along with how it's recorded in the ledger.
+type draft struct {
+	what    string // what the post is, for logs, like "sekki rikka"
+	text    string
+	langs   []string
+	markers []string   // markers of the seasons the post announces
+	tag     string     // hashtag to end the post with, if any
+	quote   recentPost // post to quote, if any
+	root    recentPost // first post of the thread the post replies in, if any
+	parent  recentPost // post the post replies to, if any
+
+	kind  string           // kind of post, for the ledger
+	group []seasons.Season // seasons the post is about
+	year  int              // year posts that aren't about a season are for
+}
+
+// fullText returns the text of the post, ending with its hashtag.
+func (d draft) fullText() string {
+	if d.tag == "" {
+		return d.text
+	}
+	return d.text + " #" + d.tag
+}
+
+// bskyAccount is a Bluesky account.
+type bskyAccount struct {
+	client *bsky.Client
+}
+
+func (a bskyAccount) since(ctx context.Context, from time.Time) ([]recentPost, error) {
+	posts, err := a.client.GetPostsSince(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	recent := make([]recentPost, len(posts))
+	for i, post := range posts {
+		recent[i] = fromBsky(post)
+	}
+	return recent, nil
+}
+
+func (a bskyAccount) publish(ctx context.Context, d draft) (recentPost, error) {
+	var opts []bskypost.BuilderOption
+	if len(d.langs) > 0 {
+		opts = append(opts, bskypost.WithLanguages(d.langs...))
+	}
+	if len(d.markers) > 0 {
+		opts = append(opts, bskypost.WithTags(d.markers...))
+	}
+	builder := bsky.NewPostBuilder(opts...).AddText(d.text)
+	if d.tag != "" {
+		builder = builder.AddSpace().AddTag(d.tag)
+	}
+	if d.quote.id != "" {
+		builder = builder.WithQuote(bskypost.Ref{URI: d.quote.id, CID: d.quote.cid})
+	}
+	if d.parent.id != "" {
+		builder = builder.WithReply(bskypost.Ref{URI: d.root.id, CID: d.root.cid}, bskypost.Ref{URI: d.parent.id, CID: d.parent.cid})
+	}
+	feedPost, err := builder.Build()
+	if err != nil {
+		return recentPost{}, fmt.Errorf("building post: %w", err)
+	}
+	res, err := a.client.PostToFeed(ctx, feedPost)
+	if err != nil {
+		return recentPost{}, err
+	}
+	return recentPost{id: res.URI, cid: res.CID}, nil
+}
+
+// mastodonAccount is a Mastodon account.
+type mastodonAccount struct {
+	client *mastodon.Client
+}
+
+func (a mastodonAccount) since(ctx context.Context, from time.Time) ([]recentPost, error) {
+	statuses, err := a.client.AccountStatuses(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	recent := make([]recentPost, len(statuses))
+	for i, status := range statuses {
+		recent[i] = fromMastodon(status)
+	}
+	return recent, nil
+}
+
+func (a mastodonAccount) publish(ctx context.Context, d draft) (recentPost, error) {
+	params := mastodon.PostStatusParams{
+		Status:      d.fullText(),
+		InReplyToID: d.parent.id,
+		// The markers stop a retried request posting the season twice.
+		IdempotencyKey: strings.Join(d.markers, ","),
+	}
+	if len(d.langs) > 0 {
+		// Statuses only have one language, so tag bilingual posts with the
+		// one they lead with.
+		params.Language = d.langs[0]
+	}
+	status, err := a.client.PostStatus(ctx, params)
+	if err != nil {
+		return recentPost{}, err
+	}
+	log.Printf("mastodon: posted! %s", status.URL)
+	return recentPost{id: status.ID, url: status.URL}, nil
+}
+
+// dryRun reads from an account without posting to it, for dev mode.
+type dryRun struct {
+	account
+	name string
+}
+
+func (a dryRun) publish(_ context.Context, d draft) (recentPost, error) {
+	log.Printf("%s: would post %s (skipping in dev mode): %s", a.name, d.what, d.fullText())
+	return recentPost{}, nil
+}
+
+// inDevMode returns acct wrapped so nothing is posted to it in dev mode.
+func inDevMode(p platform, acct account) account {
+	if *dev {
+		return dryRun{account: acct, name: p.name}
+	}
+	return acct
+}
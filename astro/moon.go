@@ -0,0 +1,73 @@
+package astro
+
+import (
+	"math"
+	"time"
+)
+
+// synodicMonth is the mean time between two new moons, in days.
+const synodicMonth = 29.530588
+
+// principalWidth is the span of elongation, in degrees, named after a
+// principal phase like the full moon: about a day, centred on the moment
+// itself.
+const principalWidth = 360 / synodicMonth
+
+// MoonPhase describes how the moon looks at a moment in time.
+type MoonPhase struct {
+	Name         string  // name of the phase, like "Waxing crescent"
+	Emoji        string  // emoji for the phase, like 🌒
+	Illumination float64 // fraction of the moon's disc that's lit, between 0 and 1
+	Age          float64 // days since the last new moon
+}
+
+// phases are the eight phases of the moon, in order. The even ones are the
+// principal phases at each quarter of the moon's orbit, and the odd ones the
+// days between them.
+var phases = []struct {
+	name  string
+	emoji string
+}{
+	{"New moon", "🌑"},
+	{"Waxing crescent", "🌒"},
+	{"First quarter", "🌓"},
+	{"Waxing gibbous", "🌔"},
+	{"Full moon", "🌕"},
+	{"Waning gibbous", "🌖"},
+	{"Last quarter", "🌗"},
+	{"Waning crescent", "🌘"},
+}
+
+// Moon returns the phase of the moon at t. The phase angle comes from the
+// moon's mean elongation with the largest periodic terms, which Meeus gives
+// as accurate to a few thousandths in illumination.
+func Moon(t time.Time) MoonPhase {
+	T := julianCenturies(t)
+	d := normalize(297.8501921 + 445267.1114034*T - 0.0018819*T*T + T*T*T/545868 - T*T*T*T/113065000)
+	m := normalize(357.5291092 + 35999.0502909*T - 0.0001536*T*T + T*T*T/24490000)
+	mm := normalize(134.9633964 + 477198.8675055*T + 0.0087414*T*T + T*T*T/69699 - T*T*T*T/14712000)
+
+	// The phase angle is the angle between the sun and the Earth as seen from
+	// the moon: 0° when full, 180° when new.
+	i := 180 - d -
+		6.289*math.Sin(rad(mm)) +
+		2.100*math.Sin(rad(m)) -
+		1.274*math.Sin(rad(2*d-mm)) -
+		0.658*math.Sin(rad(2*d)) -
+		0.214*math.Sin(rad(2*mm)) -
+		0.110*math.Sin(rad(d))
+
+	// Elongation runs from 0° at new moon, through 180° at full moon, and
+	// back round again.
+	elongation := normalize(180 - i)
+	phase := phases[2*int(elongation/90)+1]
+	if quarter := math.Round(elongation / 90); math.Abs(elongation-quarter*90) < principalWidth/2 {
+		phase = phases[2*int(quarter)%len(phases)]
+	}
+	return MoonPhase{
+		Name:         phase.name,
+		Emoji:        phase.emoji,
+		Illumination: (1 + math.Cos(rad(i))) / 2,
+		Age:          elongation / 360 * synodicMonth,
+	}
+}
@@ -0,0 +1,43 @@
+package astro
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestMoon(t *testing.T) {
+	t.Run("matches Meeus", func(t *testing.T) {
+		// Meeus, example 48.a: 1992 April 12.0 TD, illuminated fraction 0.6786.
+		tt := time.Date(1992, time.April, 12, 0, 0, 0, 0, time.UTC).Add(-59 * time.Second)
+		if got := Moon(tt).Illumination; math.Abs(got-0.6786) > 0.002 {
+			t.Errorf("expected 0.6786, got %v", got)
+		}
+	})
+
+	// Published by NASA, to the nearest minute.
+	tests := []struct {
+		name string
+		at   time.Time
+		want string
+	}{
+		{"full moon", time.Date(2024, time.March, 25, 7, 0, 0, 0, time.UTC), "Full moon"},
+		{"new moon", time.Date(2024, time.April, 8, 18, 21, 0, 0, time.UTC), "New moon"},
+		{"first quarter", time.Date(2024, time.April, 15, 19, 13, 0, 0, time.UTC), "First quarter"},
+		{"waning crescent", time.Date(2024, time.April, 5, 0, 0, 0, 0, time.UTC), "Waning crescent"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Moon(tt.at); got.Name != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got.Name)
+			}
+		})
+	}
+
+	t.Run("counts the age from the new moon", func(t *testing.T) {
+		newMoon := time.Date(2024, time.April, 8, 18, 21, 0, 0, time.UTC)
+		if got := Moon(newMoon.Add(7 * 24 * time.Hour)).Age; math.Abs(got-7) > 0.5 {
+			t.Errorf("expected an age of about 7 days, got %v", got)
+		}
+	})
+}
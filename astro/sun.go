@@ -0,0 +1,109 @@
+// Package astro contains the offline astronomical calculations used to date
+// the seasons, and to describe the sky on the day they're posted. The
+// formulas come from Jean Meeus' "Astronomical Algorithms", and place the
+// solar terms, sunrise and sunset to within a minute or so of the published
+// times.
+package astro
+
+import (
+	"math"
+	"time"
+)
+
+// tropicalYear is the mean length of the tropical year, in days.
+const tropicalYear = 365.24219
+
+// julianDay returns the Julian day number for t.
+func julianDay(t time.Time) float64 {
+	return float64(t.UnixNano())/float64(24*time.Hour) + 2440587.5
+}
+
+// fromJulianDay returns the time for the Julian day number jd.
+func fromJulianDay(jd float64) time.Time {
+	ns := (jd - 2440587.5) * float64(24*time.Hour)
+	return time.Unix(0, int64(ns)).UTC()
+}
+
+// deltaT returns the approximate difference between Terrestrial Time and
+// Universal Time in seconds, using the Espenak & Meeus polynomial for
+// 2005–2050.
+func deltaT(t time.Time) float64 {
+	y := float64(t.Year()) + (float64(t.YearDay())-0.5)/365.25 - 2000
+	return 62.92 + 0.32217*y + 0.005589*y*y
+}
+
+// julianCenturies returns the number of Julian centuries of Terrestrial Time
+// between J2000.0 and t.
+func julianCenturies(t time.Time) float64 {
+	jde := julianDay(t) + deltaT(t)/86400
+	return (jde - 2451545.0) / 36525
+}
+
+// SunLongitude returns the apparent ecliptic longitude of the sun at t, in
+// degrees between 0 and 360.
+func SunLongitude(t time.Time) float64 {
+	T := julianCenturies(t)
+	tau := T / 10
+
+	// Geometric longitude from the Earth's heliocentric position, converted
+	// to the FK5 system.
+	lon := deg(sum(earthL, tau)) + 180
+	lon -= 0.09033 / 3600
+
+	// Nutation in longitude and aberration, using the Earth's distance from
+	// the sun from the low-precision orbit.
+	omega := rad(125.04452 - 1934.136261*T)
+	ls := rad(280.4665 + 36000.7698*T)
+	lm := rad(218.3165 + 481267.8813*T)
+	nutation := -17.20*math.Sin(omega) - 1.32*math.Sin(2*ls) - 0.23*math.Sin(2*lm) + 0.21*math.Sin(2*omega)
+	aberration := -20.4898 / sunDistance(T)
+	return normalize(lon + (nutation+aberration)/3600)
+}
+
+// sunDistance returns the distance between the Earth and the sun in
+// astronomical units, T Julian centuries after J2000.0.
+func sunDistance(T float64) float64 {
+	m := rad(357.52911 + 35999.05029*T - 0.0001537*T*T)
+	e := 0.016708634 - 0.000042037*T - 0.0000001267*T*T
+	c := (1.914602-0.004817*T-0.000014*T*T)*math.Sin(m) +
+		(0.019993-0.000101*T)*math.Sin(2*m) +
+		0.000289*math.Sin(3*m)
+	v := m + rad(c)
+	return 1.000001018 * (1 - e*e) / (1 + e*math.Cos(v))
+}
+
+// SolarTerm returns the instant during year at which the sun's apparent
+// ecliptic longitude reaches longitude degrees. Terms close to 280°, where
+// the sun sits on New Year's Day, may fall on either side of the boundary.
+func SolarTerm(year int, longitude float64) time.Time {
+	// The sun is at roughly 280° at the start of January, so use that to
+	// make a first guess, then refine it until we're within a second.
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	days := normalize(longitude-280) / 360 * tropicalYear
+	jd := julianDay(start) + days
+	for i := 0; i < 20; i++ {
+		diff := normalize(longitude-SunLongitude(fromJulianDay(jd))+180) - 180
+		jd += diff / 360 * tropicalYear
+		if math.Abs(diff) < 1e-6 {
+			break
+		}
+	}
+	return fromJulianDay(jd).Round(time.Second)
+}
+
+func rad(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func deg(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
+
+// normalize returns deg wrapped into the range [0, 360).
+func normalize(deg float64) float64 {
+	deg = math.Mod(deg, 360)
+	if deg < 0 {
+		deg += 360
+	}
+	return deg
+}
@@ -0,0 +1,54 @@
+package astro
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSolarTerm(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+
+	// Published by the National Astronomical Observatory of Japan, to the
+	// nearest minute.
+	tests := []struct {
+		name      string
+		year      int
+		longitude float64
+		want      string
+	}{
+		{"risshun 2024", 2024, 315, "2024-02-04 17:27"},
+		{"shunbun 2024", 2024, 0, "2024-03-20 12:06"},
+		{"geshi 2024", 2024, 90, "2024-06-21 05:51"},
+		{"shubun 2024", 2024, 180, "2024-09-22 21:44"},
+		{"toji 2024", 2024, 270, "2024-12-21 18:21"},
+		{"risshun 2025", 2025, 315, "2025-02-03 23:10"},
+		{"shunbun 2025", 2025, 0, "2025-03-20 18:01"},
+		{"geshi 2025", 2025, 90, "2025-06-21 11:42"},
+		{"shubun 2025", 2025, 180, "2025-09-23 03:19"},
+		{"toji 2025", 2025, 270, "2025-12-22 00:03"},
+		{"risshun 2026", 2026, 315, "2026-02-04 05:02"},
+		{"shunbun 2026", 2026, 0, "2026-03-20 23:46"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, err := time.ParseInLocation("2006-01-02 15:04", tt.want, jst)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := SolarTerm(tt.year, tt.longitude)
+			if diff := got.Sub(want).Abs(); diff > time.Minute {
+				t.Errorf("expected %v, got %v (off by %v)", want, got.In(jst), diff)
+			}
+		})
+	}
+}
+
+func TestSunLongitude(t *testing.T) {
+	// Meeus, example 25.b: 1992 October 13.0 TD, apparent longitude
+	// 199°54'21.818".
+	tt := time.Date(1992, time.October, 13, 0, 0, 0, 0, time.UTC).Add(-59 * time.Second)
+	got := SunLongitude(tt)
+	if diff := got - 199.906061; diff > 0.0005 || diff < -0.0005 {
+		t.Errorf("expected 199.906061, got %v", got)
+	}
+}
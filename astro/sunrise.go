@@ -0,0 +1,77 @@
+package astro
+
+import (
+	"math"
+	"time"
+)
+
+// Place is a point on the Earth's surface, in degrees. Longitude is positive
+// east of Greenwich.
+type Place struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// Tokyo is the point the National Astronomical Observatory of Japan uses for
+// its Tokyo sunrise and sunset tables.
+var Tokyo = Place{Latitude: 35 + 39.0/60, Longitude: 139 + 44.0/60}
+
+// riseAltitude is the altitude of the sun's centre as it rises or sets,
+// allowing for refraction and the sun's semidiameter.
+const riseAltitude = -0.8333
+
+// siderealRate is how many degrees the Earth turns against the stars in a
+// day.
+const siderealRate = 360.98564736629
+
+// SunTimes returns when the sun rises and sets at p on the day of date, in
+// date's location. ok is false if the sun doesn't both rise and set that day,
+// as happens in polar summer and winter.
+func SunTimes(date time.Time, p Place) (rise, set time.Time, ok bool) {
+	y, m, d := date.Date()
+	noon := time.Date(y, m, d, 12, 0, 0, 0, date.Location())
+	rise, riseOK := sunEvent(noon, p, -1)
+	set, setOK := sunEvent(noon, p, 1)
+	if !riseOK || !setOK {
+		return time.Time{}, time.Time{}, false
+	}
+	return rise.In(date.Location()), set.In(date.Location()), true
+}
+
+// sunEvent returns when the sun rises (sign -1) or sets (sign 1) at p, closest
+// to the first guess t. It refines the guess by moving it until the sun's
+// hour angle matches the one it has when on the horizon.
+func sunEvent(t time.Time, p Place, sign float64) (time.Time, bool) {
+	lat := rad(p.Latitude)
+	for i := 0; i < 5; i++ {
+		ra, dec := sunEquatorial(t)
+		cosH := (math.Sin(rad(riseAltitude)) - math.Sin(lat)*math.Sin(dec)) / (math.Cos(lat) * math.Cos(dec))
+		if cosH < -1 || cosH > 1 {
+			return time.Time{}, false
+		}
+		target := sign * deg(math.Acos(cosH))
+		hourAngle := normalize(siderealTime(t)+p.Longitude-ra+180) - 180
+		diff := normalize(target-hourAngle+180) - 180
+		t = t.Add(time.Duration(diff / siderealRate * float64(24*time.Hour)))
+	}
+	return t.Round(time.Second), true
+}
+
+// sunEquatorial returns the sun's apparent right ascension in degrees and
+// declination in radians at t.
+func sunEquatorial(t time.Time) (ra, dec float64) {
+	T := julianCenturies(t)
+	omega := rad(125.04 - 1934.136*T)
+	obliquity := rad(23.439291 - 0.0130042*T + 0.00256*math.Cos(omega))
+	lon := rad(SunLongitude(t))
+	ra = normalize(deg(math.Atan2(math.Cos(obliquity)*math.Sin(lon), math.Cos(lon))))
+	dec = math.Asin(math.Sin(obliquity) * math.Sin(lon))
+	return ra, dec
+}
+
+// siderealTime returns the mean sidereal time at Greenwich at t, in degrees.
+func siderealTime(t time.Time) float64 {
+	jd := julianDay(t)
+	T := (jd - 2451545.0) / 36525
+	return normalize(280.46061837 + siderealRate*(jd-2451545.0) + 0.000387933*T*T - T*T*T/38710000)
+}
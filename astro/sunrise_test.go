@@ -0,0 +1,54 @@
+package astro
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSunTimes(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+
+	// Published by the National Astronomical Observatory of Japan for Tokyo,
+	// to the nearest minute.
+	tests := []struct {
+		date string
+		rise string
+		set  string
+	}{
+		{"2024-03-20", "05:45", "17:53"},
+		{"2024-06-21", "04:25", "19:00"},
+		{"2024-12-21", "06:47", "16:32"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.date, func(t *testing.T) {
+			date, err := time.ParseInLocation(time.DateOnly, tt.date, jst)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rise, set, ok := SunTimes(date, Tokyo)
+			if !ok {
+				t.Fatal("expected the sun to rise and set")
+			}
+			for _, c := range []struct {
+				name string
+				got  time.Time
+				want string
+			}{{"sunrise", rise, tt.rise}, {"sunset", set, tt.set}} {
+				want, err := time.ParseInLocation("2006-01-02 15:04", tt.date+" "+c.want, jst)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff := c.got.Sub(want).Abs(); diff > time.Minute {
+					t.Errorf("expected %s at %v, got %v", c.name, want, c.got)
+				}
+			}
+		})
+	}
+
+	t.Run("has no sunrise in polar night", func(t *testing.T) {
+		date := time.Date(2024, time.December, 21, 0, 0, 0, 0, time.UTC)
+		if _, _, ok := SunTimes(date, Place{Latitude: 78.2, Longitude: 15.6}); ok {
+			t.Error("expected no sunrise in Svalbard in December")
+		}
+	})
+}
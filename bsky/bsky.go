@@ -79,36 +79,86 @@ func (c *Client) Close() error {
 }
 
 type BlueskyPost struct {
+	URI          string
 	CID          string
 	AuthorDid    string
 	AuthorHandle string
 	Created      time.Time
+	Text         string
+	Tags         []string // tags on the post record
 }
 
 func (c *Client) GetPosts(ctx context.Context) ([]*BlueskyPost, error) {
+	posts, _, err := c.authorFeed(ctx, "", 10)
+	return posts, err
+}
+
+// GetPostsSince returns every post the account has made since since, newest
+// first, paging back through its feed as far as needed.
+func (c *Client) GetPostsSince(ctx context.Context, since time.Time) ([]*BlueskyPost, error) {
+	var all []*BlueskyPost
+	cursor := ""
+	for {
+		posts, next, err := c.authorFeed(ctx, cursor, 100)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range posts {
+			if p.Created.Before(since) {
+				return all, nil
+			}
+			all = append(all, p)
+		}
+		if next == "" || len(posts) == 0 {
+			return all, nil
+		}
+		cursor = next
+	}
+}
+
+// authorFeed returns a page of the account's own posts, leaving out reposts,
+// along with the cursor for the next page.
+func (c *Client) authorFeed(ctx context.Context, cursor string, limit int64) ([]*BlueskyPost, string, error) {
 	profile, err := appbsky.ActorGetProfile(ctx, c.client, c.handle)
 	if err != nil {
-		return nil, fmt.Errorf("getting profile: %w", err)
+		return nil, "", fmt.Errorf("getting profile: %w", err)
 	}
-	resp, err := appbsky.FeedGetAuthorFeed(ctx, c.client, profile.Did, "", "", false, 10)
+	resp, err := appbsky.FeedGetAuthorFeed(ctx, c.client, profile.Did, cursor, "", false, limit)
 	if err != nil {
-		return nil, fmt.Errorf("getting posts: %w", err)
+		return nil, "", fmt.Errorf("getting posts: %w", err)
 	}
 	posts := make([]*BlueskyPost, 0, len(resp.Feed))
 	for _, feed := range resp.Feed {
 		p := feed.Post
+		if p.Author.Did != profile.Did {
+			continue
+		}
 		t, err := time.Parse(time.RFC3339, p.IndexedAt)
 		if err != nil {
-			return nil, fmt.Errorf("parsing post timestamp: %w", err)
+			return nil, "", fmt.Errorf("parsing post timestamp: %w", err)
+		}
+		var text string
+		var tags []string
+		if p.Record != nil {
+			if record, ok := p.Record.Val.(*appbsky.FeedPost); ok {
+				text, tags = record.Text, record.Tags
+			}
 		}
 		posts = append(posts, &BlueskyPost{
+			URI:          p.Uri,
 			CID:          p.Cid,
 			AuthorDid:    p.Author.Did,
 			AuthorHandle: p.Author.Handle,
 			Created:      t,
+			Text:         text,
+			Tags:         tags,
 		})
 	}
-	return posts, nil
+	var next string
+	if resp.Cursor != nil {
+		next = *resp.Cursor
+	}
+	return posts, next, nil
 }
 
 type PostResponse struct {
@@ -0,0 +1,12 @@
+package bsky
+
+import "github.com/rivo/uniseg"
+
+// MaxPostLength is the maximum length of a Bluesky post, in graphemes.
+const MaxPostLength = 300
+
+// PostLength returns the length of text as Bluesky counts it, in grapheme
+// clusters, so an emoji made of several code points counts as one.
+func PostLength(text string) int {
+	return uniseg.GraphemeClusterCount(text)
+}
@@ -0,0 +1,20 @@
+package bsky
+
+import "testing"
+
+func TestPostLength(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"Rain waters.", 12},
+		{"立春", 2},
+		{"☀️", 1},
+		{"👨‍👩‍👧", 1},
+	}
+	for _, tt := range tests {
+		if got := PostLength(tt.text); got != tt.want {
+			t.Errorf("PostLength(%q): expected %d, got %d", tt.text, tt.want, got)
+		}
+	}
+}
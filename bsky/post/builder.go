@@ -10,6 +10,7 @@ import (
 	"time"
 	"unicode"
 
+	"github.com/bluesky-social/indigo/api/atproto"
 	"github.com/bluesky-social/indigo/api/bsky"
 	lexutil "github.com/bluesky-social/indigo/lex/util"
 	"github.com/watzon/lining/models"
@@ -60,6 +61,11 @@ type BuilderOptions struct {
 	AutoLink bool
 	// DefaultLanguage sets the default language for the post
 	DefaultLanguage string
+	// Languages sets every language the post is written in, in place of the
+	// default language
+	Languages []string
+	// Tags sets tags on the post record, which aren't shown in its text
+	Tags []string
 }
 
 // BuilderOption is a function that configures a BuilderOptions struct
@@ -110,6 +116,22 @@ func WithDefaultLanguage(lang string) BuilderOption {
 	}
 }
 
+// WithLanguages returns a BuilderOption that sets the languages the post is
+// written in
+func WithLanguages(langs ...string) BuilderOption {
+	return func(opts *BuilderOptions) {
+		opts.Languages = langs
+	}
+}
+
+// WithTags returns a BuilderOption that sets tags on the post record, which
+// aren't shown in its text
+func WithTags(tags ...string) BuilderOption {
+	return func(opts *BuilderOptions) {
+		opts.Tags = tags
+	}
+}
+
 // DefaultOptions returns the default BuilderOptions
 func DefaultOptions() BuilderOptions {
 	return BuilderOptions{
@@ -140,10 +162,27 @@ func DefaultOptions() BuilderOptions {
 type Builder struct {
 	segments []segment
 	embed    models.Embed
+	quote    *Ref
+	reply    *bsky.FeedPost_ReplyRef
 	err      error
 	options  BuilderOptions
 }
 
+// Ref identifies an existing post, by the URI and CID it was created with.
+type Ref struct {
+	URI string
+	CID string
+}
+
+// strongRef returns the record reference for the post.
+func (r Ref) strongRef() *atproto.RepoStrongRef {
+	return &atproto.RepoStrongRef{
+		LexiconTypeID: "com.atproto.repo.strongRef",
+		Uri:           r.URI,
+		Cid:           r.CID,
+	}
+}
+
 // segment represents a piece of text with an optional facet.
 // When the facet is nil, the segment is treated as plain text.
 type segment struct {
@@ -545,6 +584,23 @@ func (b *Builder) WithImages(blobs []lexutil.LexBlob, images []models.Image) *Bu
 	return b
 }
 
+// WithQuote quotes an existing post, which is displayed as an embedded card.
+// Quotes take the place of any images or link card.
+func (b *Builder) WithQuote(quoted Ref) *Builder {
+	b.quote = &quoted
+	return b
+}
+
+// WithReply makes the post a reply to parent, in the thread started by root.
+// For a direct reply to the first post of a thread, both are the same.
+func (b *Builder) WithReply(root, parent Ref) *Builder {
+	b.reply = &bsky.FeedPost_ReplyRef{
+		Root:   root.strongRef(),
+		Parent: parent.strongRef(),
+	}
+	return b
+}
+
 // shouldAddSpace returns true if a space should be added between segments
 func (b *Builder) shouldAddSpace(curr, next string) bool {
 	return curr != "" && next != ""
@@ -615,10 +671,23 @@ func (b *Builder) Build() (bsky.FeedPost, error) {
 		Facets:        facets,
 		LexiconTypeID: "app.bsky.feed.post",
 		CreatedAt:     time.Now().Format(time.RFC3339),
+		Langs:         b.options.Languages,
+		Reply:         b.reply,
+		Tags:          b.options.Tags,
+	}
+	if len(post.Langs) == 0 && b.options.DefaultLanguage != "" {
+		post.Langs = []string{b.options.DefaultLanguage}
 	}
 
 	// Handle embeds
-	if len(b.embed.Images) > 0 && len(b.embed.Images) == len(b.embed.UploadedImages) {
+	if b.quote != nil {
+		post.Embed = &bsky.FeedPost_Embed{
+			EmbedRecord: &bsky.EmbedRecord{
+				LexiconTypeID: "app.bsky.embed.record",
+				Record:        b.quote.strongRef(),
+			},
+		}
+	} else if len(b.embed.Images) > 0 && len(b.embed.Images) == len(b.embed.UploadedImages) {
 		images := make([]*bsky.EmbedImages_Image, len(b.embed.Images))
 		for i, img := range b.embed.Images {
 			images[i] = &bsky.EmbedImages_Image{
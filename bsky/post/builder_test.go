@@ -492,6 +492,38 @@ func TestBuilder(t *testing.T) {
 	})
 }
 
+func TestBuilderThreads(t *testing.T) {
+	root := Ref{URI: "at://did:plc:test/app.bsky.feed.post/root", CID: "root"}
+	quoted := Ref{URI: "at://did:plc:test/app.bsky.feed.post/quoted", CID: "quoted"}
+
+	t.Run("quotes a post", func(t *testing.T) {
+		post, err := NewBuilder().AddText("Start of spring").WithQuote(quoted).Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if post.Embed == nil || post.Embed.EmbedRecord == nil {
+			t.Fatalf("expected a record embed, got %v", post.Embed)
+		}
+		if got := post.Embed.EmbedRecord.Record.Uri; got != quoted.URI {
+			t.Errorf("expected quoted URI %s, got %s", quoted.URI, got)
+		}
+	})
+
+	t.Run("replies to a thread", func(t *testing.T) {
+		parent := Ref{URI: "at://did:plc:test/app.bsky.feed.post/parent", CID: "parent"}
+		post, err := NewBuilder().AddText("Rain waters").WithReply(root, parent).Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if post.Reply == nil {
+			t.Fatal("expected a reply")
+		}
+		if post.Reply.Root.Cid != "root" || post.Reply.Parent.Cid != "parent" {
+			t.Errorf("expected root and parent CIDs, got %s and %s", post.Reply.Root.Cid, post.Reply.Parent.Cid)
+		}
+	})
+}
+
 func TestBuilderJoinStrategies(t *testing.T) {
 	t.Run("JoinAsIs strategy", func(t *testing.T) {
 		post, err := NewBuilder().
@@ -574,6 +606,36 @@ func TestBuilderOptions(t *testing.T) {
 		}
 	})
 
+	t.Run("tags posts with their languages", func(t *testing.T) {
+		post, err := NewBuilder().AddText("Hello").Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(post.Langs) != 1 || post.Langs[0] != "en" {
+			t.Errorf("expected Langs [en], got %v", post.Langs)
+		}
+		post, err = NewBuilder(WithLanguages("en", "ja")).AddText("Hello, こんにちは").Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(post.Langs) != 2 || post.Langs[1] != "ja" {
+			t.Errorf("expected Langs [en ja], got %v", post.Langs)
+		}
+	})
+
+	t.Run("tags the post record", func(t *testing.T) {
+		post, err := NewBuilder(WithTags("sekki:risshun:2026")).AddText("Start of spring").Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(post.Tags) != 1 || post.Tags[0] != "sekki:risshun:2026" {
+			t.Errorf("expected Tags [sekki:risshun:2026], got %v", post.Tags)
+		}
+		if post.Text != "Start of spring" {
+			t.Errorf("expected tags to stay out of the text, got %q", post.Text)
+		}
+	})
+
 	t.Run("multiple options (future-proofing)", func(t *testing.T) {
 		// This test ensures our options system can handle multiple options
 		// when we add more in the future
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/rosszurowski/small-seasons-bot/seasons"
+)
+
+// lateTemplate renders the line leading a season posted late.
+var lateTemplate = seasons.MustParseTemplate("late", seasons.LateTemplate)
+
+// missed returns the first season p missed while the bot wasn't running that
+// it should catch up on at now, going by its catch-up policy and posted. It
+// logs when there's no season post to catch up from, since nothing is caught
+// up on then.
+func (p platform) missed(cal *seasons.Calendar, now time.Time, posted func(seasons.Season) bool) (seasons.Season, bool) {
+	found := false
+	missed := cal.Missed(now, p.catchUp, func(s seasons.Season) bool {
+		ok := posted(s)
+		found = found || ok
+		return ok
+	})
+	if !found && p.catchUp != seasons.CatchUpSkip {
+		log.Printf("%s: found no season post from the past year to catch up from, so not catching up", p.name)
+	}
+	if len(missed) == 0 {
+		return seasons.Season{}, false
+	}
+	return missed[0], true
+}
+
+// withLate returns text led by a line saying when s began, for posting it
+// late at now. Accounts in other locales, and posts with no room for the
+// line, go out without it.
+func withLate(cal *seasons.Calendar, p platform, s seasons.Season, now time.Time, text string) (string, error) {
+	if p.locale != seasons.English {
+		log.Printf("%s: late posts are only marked in English, posting %s %s without", p.name, s.Kind, s.ID)
+		return text, nil
+	}
+	line, err := lateTemplate.Execute(cal.Late(s, now))
+	if err != nil {
+		return "", fmt.Errorf("rendering late line for %s: %w", s.ID, err)
+	}
+	late := line + " " + text
+	if p.length(late) > p.maxLength {
+		log.Printf("%s: no room to mark %s %s as late", p.name, s.Kind, s.ID)
+		return text, nil
+	}
+	return late, nil
+}
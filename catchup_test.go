@@ -0,0 +1,50 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rosszurowski/small-seasons-bot/seasons"
+)
+
+func TestCatchUp(t *testing.T) {
+	cal, err := seasons.Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	loc := cal.PostTime().Location
+	// Risshun 2027 and its first kō were posted on February 4th, and the bot
+	// was down until March 1st.
+	posted := time.Date(2027, time.February, 4, 0, 0, 0, 0, loc)
+	now := time.Date(2027, time.March, 1, 16, 2, 0, 0, loc)
+	var recent []recentPost
+	for _, s := range cal.InRange(posted, posted.AddDate(0, 0, 1)) {
+		recent = append(recent, recentPost{markers: []string{s.Marker()}, created: s.Date})
+	}
+	if len(recent) != 2 {
+		t.Fatalf("expected risshun and its first kō, got %d seasons", len(recent))
+	}
+
+	t.Run("reads back a year of posts", func(t *testing.T) {
+		p := testPlatform(t, seasons.English, 1000)
+		if got, want := p.historySince(now), now.AddDate(0, 0, -historyDays); !got.Equal(want) {
+			t.Errorf("expected %v when skipping, got %v", want, got)
+		}
+		p.catchUp = seasons.CatchUpLate
+		if got, want := p.historySince(now), now.AddDate(-1, 0, 0); !got.Equal(want) {
+			t.Errorf("expected %v when catching up, got %v", want, got)
+		}
+	})
+
+	t.Run("catches up after more than two weeks down", func(t *testing.T) {
+		p := testPlatform(t, seasons.English, 1000)
+		p.catchUp = seasons.CatchUpLate
+		group, late, err := p.postable(cal, now, recent, nil)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(group) != 1 || group[0].ID != "koo-kenkansu" || !late {
+			t.Errorf("expected koo-kenkansu late, got %v (late %v)", group, late)
+		}
+	})
+}
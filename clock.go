@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// clock tells the time the bot runs at.
+type clock interface {
+	Now() time.Time
+}
+
+// systemClock is the machine's clock.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// shiftedClock keeps pace with the machine's clock from a different starting
+// point, to run as if at another time.
+type shiftedClock struct {
+	offset time.Duration
+}
+
+func (c shiftedClock) Now() time.Time {
+	return time.Now().Add(c.offset).Round(0)
+}
+
+// newClock returns the clock given by the -now flag, or the machine's clock
+// if it isn't set. Times without a zone are read in loc.
+func newClock(loc *time.Location) (clock, error) {
+	if *nowAt == "" {
+		return systemClock{}, nil
+	}
+	t, err := parseInstant(*nowAt, loc)
+	if err != nil {
+		return nil, fmt.Errorf("parsing -now: %w", err)
+	}
+	return shiftedClock{offset: time.Until(t)}, nil
+}
+
+// instantLayouts are the layouts parseInstant accepts, from the most precise.
+var instantLayouts = []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02 15:04", time.DateOnly}
+
+// parseInstant parses an instant given as an RFC 3339 time, a date and time
+// of day like "2027-02-04 16:02", or a date like "2027-02-04" for its
+// midnight. Times without a zone are read in loc.
+func parseInstant(s string, loc *time.Location) (time.Time, error) {
+	for _, layout := range instantLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: must be like 2027-02-04, 2027-02-04 16:02 or 2027-02-04T16:02:00+09:00", s)
+}
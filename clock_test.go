@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseInstant(t *testing.T) {
+	jst, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2027, time.February, 4, 16, 2, 0, 0, jst)
+
+	for _, tt := range []struct {
+		name string
+		in   string
+		want time.Time
+	}{
+		{"reads RFC 3339 times", "2027-02-04T07:02:00Z", want},
+		{"reads times without a zone in the location", "2027-02-04T16:02", want},
+		{"reads times with a space", "2027-02-04 16:02", want},
+		{"reads dates as their midnight", "2027-02-04", time.Date(2027, time.February, 4, 0, 0, 0, 0, jst)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseInstant(tt.in, jst)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("rejects other layouts", func(t *testing.T) {
+		if _, err := parseInstant("February 4th", jst); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func TestShiftedClock(t *testing.T) {
+	t.Run("keeps pace from its offset", func(t *testing.T) {
+		c := shiftedClock{offset: 24 * time.Hour}
+		if d := c.Now().Sub(time.Now()); d < 24*time.Hour-time.Second || d > 24*time.Hour+time.Second {
+			t.Errorf("expected a day ahead, got %v", d)
+		}
+	})
+
+	t.Run("starts at -now", func(t *testing.T) {
+		was := *nowAt
+		*nowAt = "2027-02-04T16:02:00+09:00"
+		t.Cleanup(func() { *nowAt = was })
+		c, err := newClock(time.UTC)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		want := time.Date(2027, time.February, 4, 7, 2, 0, 0, time.UTC)
+		if d := c.Now().Sub(want); d < 0 || d > time.Second {
+			t.Errorf("expected %v, got %v", want, c.Now())
+		}
+	})
+}
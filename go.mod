@@ -5,10 +5,13 @@ go 1.22
 toolchain go1.23.3
 
 require (
+	github.com/BurntSushi/toml v1.4.0
 	github.com/bluesky-social/indigo v0.0.0-20241122170530-feceb364ee49
 	github.com/joho/godotenv v1.5.1
+	github.com/rivo/uniseg v0.4.7
 	github.com/watzon/lining v0.0.0-20241126031058-67a9a00aa9eb
 	golang.org/x/sync v0.7.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/rosszurowski/small-seasons-bot/seasons"
+)
+
+// kigoTemplate renders word of the day posts.
+var kigoTemplate = seasons.MustParseTemplate("kigo", seasons.KigoTemplate)
+
+// nextKigo returns the text of the word of the day post to make at now,
+// without its hashtag, or false if there's none to make. timestamps are the
+// times of the account's season posts, including any just made, and posted
+// its recent word of the day posts.
+func nextKigo(cal *seasons.Calendar, p platform, saijiki *seasons.Saijiki, now time.Time, timestamps []time.Time, posted map[time.Time]string) (string, bool, error) {
+	if p.locale != seasons.English {
+		log.Printf("%s: kigo are only glossed in English, skipping word of the day", p.name)
+		return "", false, nil
+	}
+	for _, t := range timestamps {
+		// The season post is the day's post.
+		if cal.PostTime().SameDay(t, now) {
+			log.Printf("%s: posted a season today, skipping word of the day", p.name)
+			return "", false, nil
+		}
+	}
+	k, s, err := cal.Kigo(saijiki, now, posted)
+	if err != nil {
+		switch {
+		case errors.Is(err, seasons.ErrAlreadyPosted):
+			log.Printf("%s: already posted a word of the day today", p.name)
+		case errors.Is(err, seasons.ErrNoSeason):
+			log.Printf("%s: no word of the day to post yet", p.name)
+		case errors.Is(err, seasons.ErrNoKigo):
+			log.Printf("%s: no kigo left for %s %s", p.name, s.Kind, s.ID)
+		default:
+			return "", false, fmt.Errorf("getting kigo: %w", err)
+		}
+		return "", false, nil
+	}
+	text, err := kigoTemplate.Execute(seasons.KigoData{Kigo: k, Season: s})
+	if err != nil {
+		return "", false, fmt.Errorf("rendering kigo %s: %w", k.Word, err)
+	}
+	return text, true, nil
+}
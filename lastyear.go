@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/rosszurowski/small-seasons-bot/ledger"
+	"github.com/rosszurowski/small-seasons-bot/seasons"
+)
+
+// findSeasonPost returns the index of the post among posts that announced s,
+// or -1 if there isn't one. A post announced s if it carries the season's
+// marker or its hashtag. Failing that, it announced s if it contains the
+// text s is posted with now, or if it went out on the day s was due and
+// mentions its title, which catches posts made before a template changed.
+func findSeasonPost(cal *seasons.Calendar, p platform, s seasons.Season, posts []recentPost) (int, error) {
+	marker, hashtag := s.Marker(), s.Hashtag()
+	for i, post := range posts {
+		if slices.Contains(post.markers, marker) || slices.Contains(post.markers, hashtag) {
+			return i, nil
+		}
+	}
+	text, _, err := p.render(cal, []seasons.Season{s})
+	if err != nil {
+		return -1, err
+	}
+	for i, post := range posts {
+		if strings.Contains(post.text, text) {
+			return i, nil
+		}
+	}
+	for i, post := range posts {
+		if cal.PostTime().SameDay(post.created, s.Date) && strings.Contains(post.text, s.Title) {
+			return i, nil
+		}
+	}
+	return -1, nil
+}
+
+// lastYear returns the post announcing the first season of group a year ago,
+// and false if there isn't one. It's looked up in the ledger, or failing that,
+// among acct's posts.
+func (p platform) lastYear(ctx context.Context, acct account, cal *seasons.Calendar, group []seasons.Season) (recentPost, bool, error) {
+	prev, ok := cal.YearBefore(group[0])
+	if !ok {
+		return recentPost{}, false, nil
+	}
+	if e, ok := p.ledger.Find(p.name, ledger.KindSeason, prev.ID, prev.Date.Year()); ok {
+		return fromEntry(e), true, nil
+	}
+	posts, err := acct.since(ctx, prev.Date.AddDate(0, 0, -1))
+	if err != nil {
+		return recentPost{}, false, fmt.Errorf("getting posts: %w", err)
+	}
+	i, err := findSeasonPost(cal, p, prev, posts)
+	if err != nil || i < 0 {
+		return recentPost{}, false, err
+	}
+	return posts[i], true, nil
+}
+
+// withLastYear returns text with a link to last year's post at url added, if
+// it fits within p's limit.
+func withLastYear(p platform, text, url string) string {
+	linked := text + "\n\nOn this day last year: " + url
+	if p.length(linked) > p.maxLength {
+		log.Printf("%s: no room to link to last year's post", p.name)
+		return text
+	}
+	return linked
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rosszurowski/small-seasons-bot/seasons"
+)
+
+func TestFindSeasonPost(t *testing.T) {
+	cal, err := seasons.Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	usui, ok := cal.Only(seasons.KindSekki).Current(time.Date(2026, time.February, 20, 0, 0, 0, 0, cal.PostTime().Location))
+	if !ok {
+		t.Fatal("expected a season")
+	}
+	p := testPlatform(t, seasons.English, 1000)
+	text, _, err := p.render(cal, []seasons.Season{usui})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := recentPost{text: "Something else", created: usui.Date}
+
+	for _, tt := range []struct {
+		name string
+		post recentPost
+		want bool
+	}{
+		{"finds posts by their marker", recentPost{text: "edited", markers: []string{usui.Marker()}, created: usui.Date.AddDate(0, 0, 3)}, true},
+		{"finds posts by their hashtag", recentPost{text: "edited", markers: []string{usui.Hashtag()}, created: usui.Date.AddDate(0, 0, 3)}, true},
+		{"falls back to the text", recentPost{text: text, created: usui.Date}, true},
+		{"falls back to the title on the day", recentPost{text: usui.Title + " in an old template", created: usui.Date}, true},
+		{"ignores the title on other days", recentPost{text: usui.Title + " in an old template", created: usui.Date.AddDate(0, 0, 3)}, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			i, err := findSeasonPost(cal, p, usui, []recentPost{other, tt.post})
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if got := i == 1; got != tt.want {
+				t.Errorf("expected found %v, got index %d", tt.want, i)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+// Package ledger keeps a record of everything the bot has posted, so it can
+// tell what's already gone out without reading it back from each platform.
+package ledger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Kinds of post recorded in the ledger.
+const (
+	KindSeason  = "season"  // a season post
+	KindPreview = "preview" // a post announcing a season ahead of time
+	KindKigo    = "kigo"    // a word of the day post
+	KindReview  = "review"  // a post in the year in review thread
+)
+
+// Entry records one post on one platform. A post covering several seasons
+// has an entry for each of them.
+type Entry struct {
+	Platform string    `json:"platform"`
+	Kind     string    `json:"kind"`
+	Season   string    `json:"season,omitempty"` // ID of the season posted
+	Year     int       `json:"year,omitempty"`   // year the season was posted for
+	ID       string    `json:"id"`               // status ID on Mastodon, record URI on Bluesky
+	CID      string    `json:"cid,omitempty"`    // content ID of Bluesky records
+	URL      string    `json:"url,omitempty"`
+	Text     string    `json:"text,omitempty"` // text of word of the day posts, to tell which words have gone out
+	Posted   time.Time `json:"posted"`
+}
+
+// Ledger is a record of posts, kept in a JSON file. It's safe to use from
+// several goroutines. A nil Ledger records nothing and finds nothing, so
+// callers don't need to check whether one is configured.
+type Ledger struct {
+	path string
+
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// Open opens the ledger kept at path, which is created on the first post if
+// it doesn't exist yet.
+func Open(path string) (*Ledger, error) {
+	l := &Ledger{path: path}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return l, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading ledger: %w", err)
+	}
+	if err := json.Unmarshal(b, &l.entries); err != nil {
+		return nil, fmt.Errorf("decoding ledger %s: %w", path, err)
+	}
+	return l, nil
+}
+
+// Record adds e to the ledger and saves it. The file is replaced in one step,
+// so a crash part way through never leaves it half written.
+func (l *Ledger) Record(e Entry) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, e)
+	b, err := json.MarshalIndent(l.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding ledger: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(l.path), filepath.Base(l.path)+".*")
+	if err != nil {
+		return fmt.Errorf("saving ledger: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(b, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("saving ledger: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("saving ledger: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), l.path); err != nil {
+		return fmt.Errorf("saving ledger: %w", err)
+	}
+	return nil
+}
+
+// Entries returns every entry for platform, in the order they were recorded.
+func (l *Ledger) Entries(platform string) []Entry {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var entries []Entry
+	for _, e := range l.entries {
+		if e.Platform == platform {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// Find returns the most recent entry of the given kind for a season and
+// year on platform.
+func (l *Ledger) Find(platform, kind, season string, year int) (Entry, bool) {
+	entries := l.Entries(platform)
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		if e.Kind == kind && e.Season == season && e.Year == year {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
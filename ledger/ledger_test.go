@@ -0,0 +1,60 @@
+package ledger
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLedger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	posted := time.Date(2026, time.February, 4, 7, 2, 0, 0, time.UTC)
+
+	t.Run("starts empty", func(t *testing.T) {
+		l, err := Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if entries := l.Entries("bsky"); len(entries) != 0 {
+			t.Errorf("expected no entries, got %v", entries)
+		}
+	})
+
+	t.Run("keeps entries across runs", func(t *testing.T) {
+		l, err := Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := l.Record(Entry{Platform: "bsky", Kind: KindSeason, Season: "risshun", Year: 2026, ID: "at://post", Posted: posted}); err != nil {
+			t.Fatal(err)
+		}
+		if err := l.Record(Entry{Platform: "mastodon", Kind: KindSeason, Season: "risshun", Year: 2026, ID: "1", Posted: posted}); err != nil {
+			t.Fatal(err)
+		}
+
+		reopened, err := Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		e, ok := reopened.Find("bsky", KindSeason, "risshun", 2026)
+		if !ok || e.ID != "at://post" || !e.Posted.Equal(posted) {
+			t.Errorf("expected the bsky post, got %v %v", e, ok)
+		}
+		if _, ok := reopened.Find("bsky", KindSeason, "risshun", 2025); ok {
+			t.Error("expected nothing for 2025")
+		}
+		if entries := reopened.Entries("mastodon"); len(entries) != 1 {
+			t.Errorf("expected 1 mastodon entry, got %d", len(entries))
+		}
+	})
+
+	t.Run("does nothing when nil", func(t *testing.T) {
+		var l *Ledger
+		if err := l.Record(Entry{Platform: "bsky"}); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+		if _, ok := l.Find("bsky", KindSeason, "risshun", 2026); ok {
+			t.Error("expected nothing found")
+		}
+	})
+}
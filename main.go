@@ -2,237 +2,367 @@ package main
 
 import (
 	"context"
-	_ "embed"
-	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"os"
 	"time"
+	_ "time/tzdata"
 
 	_ "github.com/joho/godotenv/autoload"
 	"github.com/rosszurowski/small-seasons-bot/bsky"
+	"github.com/rosszurowski/small-seasons-bot/ledger"
 	"github.com/rosszurowski/small-seasons-bot/mastodon"
+	"github.com/rosszurowski/small-seasons-bot/seasons"
 	"golang.org/x/sync/errgroup"
 )
 
-//go:embed sekki.json
-var sekkiJSON string
-
-var dev = flag.Bool("dev", false, "run in dev mode")
-
 var (
-	ErrAlreadyPosted = errors.New("already posted")
-	ErrNoSeason      = errors.New("no season to post")
+	dev        = flag.Bool("dev", false, "run in dev mode")
+	nowAt      = flag.String("now", "", `run as if at this time, like "2027-02-04 16:02" in the post time's zone, or an RFC 3339 time; implies -dev`)
+	postAt     = flag.String("post-at", "16:02", `when to post: "start" for the moment a season starts, a time of day like "16:02", or an offset from the start like "+2h"`)
+	timezone   = flag.String("timezone", "", "IANA timezone used to decide which day a season starts on (defaults to the calendar's own zone)")
+	calendar   = flag.String("calendar", "sekki", "calendar to post: sekki (Japanese), jieqi (Chinese), jeolgi (Korean) or wheel (the Wheel of the Year)")
+	hemisphere = flag.String("hemisphere", "north", `hemisphere to keep the calendar for: "north", or "south" to shift every season by six months`)
+	sameDay    = flag.String("same-day", "order", `how to post seasons that fall on the same day: "order" to post each in turn, or "combine" to post them together`)
+	catchUp    = flag.String("catch-up", "skip", `what to do with seasons missed while the bot was down: "skip" them, post them "late", or post them late only while they're "current"`)
+	dataPath   = flag.String("seasons", "", "path to a JSON, YAML or TOML season file, or a directory of them, to use in place of the calendar's built-in seasons")
+
+	bilingual        = flag.Bool("bilingual", false, "add each season's Japanese text to posts by English accounts, where it fits")
+	kigo             = flag.Bool("kigo", false, "on days without a season, post a seasonal word from the current season")
+	lastYear         = flag.Bool("last-year", false, "quote or link to last year's post for each season")
+	state            = flag.String("state", "", "path to a JSON file recording every post, used to tell what's already gone out, and started off from each account's recent posts")
+	review           = flag.Bool("review", false, "on New Year's Eve, post a thread looking back on the year's seasons")
+	almanac          = flag.Bool("almanac", false, "add the moon phase and the day's sunrise and sunset to posts without a template of their own")
+	bskyTemplate     = flag.String("bsky-template", "", "path to a text/template file for Bluesky posts")
+	mastodonTemplate = flag.String("mastodon-template", "", "path to a text/template file for Mastodon posts")
 )
 
-type rawSeason struct {
-	ID          string
-	Title       string
-	Description string
-	StartDate   string
-	Emoji       string
-}
+// historyDays is how far back to look through an account's posts for the
+// seasons and words it's already posted, long enough to cover a sekki.
+const historyDays = 16
 
-type Season struct {
-	ID      string
-	Date    time.Time // date this year to post the post at
-	Content string    // raw post text
+// templates are the post templates for each platform. A platform without a
+// template of its own has a nil one, and gets the default for its locale.
+type templates struct {
+	bsky     *seasons.Template
+	mastodon *seasons.Template
+	japanese *seasons.Template // Japanese text for bilingual posts
+	catalog  *seasons.Catalog  // translations for accounts in other locales
+	saijiki  *seasons.Saijiki  // kigo for word of the day posts
 }
 
 func main() {
 	flag.Parse()
 
-	seasons, err := loadSeasons()
+	postTime, err := seasons.ParsePostTime(*postAt, *timezone)
+	if err != nil {
+		log.Fatal(err)
+	}
+	h, err := seasons.ParseHemisphere(*hemisphere)
+	if err != nil {
+		log.Fatal(err)
+	}
+	sd, err := seasons.ParseSameDay(*sameDay)
+	if err != nil {
+		log.Fatal(err)
+	}
+	opts := []seasons.Option{seasons.WithPostTime(postTime), seasons.WithHemisphere(h), seasons.WithSameDay(sd)}
+	cal, err := loadCalendar(opts...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	tmpls, err := loadTemplates()
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	now := time.Now()
+	var led *ledger.Ledger
+	if *state != "" {
+		if led, err = ledger.Open(*state); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	clk, err := newClock(cal.PostTime().Location)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if _, ok := clk.(shiftedClock); ok && !*dev {
+		log.Println("Running at -now, so posting nothing (dev mode)")
+		*dev = true
+	}
+
+	ctx := context.Background()
+	now := clk.Now()
+	switch cmd := flag.Arg(0); cmd {
+	case "":
+		err = post(ctx, cal, tmpls, led, now)
+	case "serve":
+		err = serve(ctx, clk, cal, tmpls, led, opts)
+	case "simulate":
+		err = simulate(ctx, cal, tmpls, now, flag.Args()[1:], os.Stdout)
+	case "validate":
+		err = validate(ctx, cal, tmpls, now, os.Stdout)
+	case "translations":
+		err = translations(cal, tmpls.catalog, os.Stdout)
+	default:
+		err = fmt.Errorf("unknown command %q", cmd)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// post posts any season that's due to every configured account, recording
+// each post in led.
+func post(ctx context.Context, cal *seasons.Calendar, tmpls templates, led *ledger.Ledger, now time.Time) error {
 	var wg errgroup.Group
 	wg.Go(func() error {
-		baseURL := os.Getenv("MASTODON_BASE_URL")
-		if baseURL == "" {
-			log.Println("No MASTODON_BASE_URL, skipping…")
-			return nil
-		}
-		accessToken := os.Getenv("MASTODON_ACCESS_TOKEN")
-		if accessToken == "" {
-			log.Println("No MASTODON_ACCESS_TOKEN, skipping…")
-			return nil
+		client, err := newMastodonClient()
+		if err != nil || client == nil {
+			return err
 		}
-
-		client, err := mastodon.NewClient(mastodon.Config{
-			BaseURL:     baseURL,
-			AccessToken: accessToken,
-		})
+		p, err := mastodonPlatform(ctx, client, tmpls)
 		if err != nil {
-			return fmt.Errorf("creating mastodon client: %w", err)
+			return err
 		}
-		if err := postToMastodon(context.Background(), client, seasons, now); err != nil {
+		p.ledger = led
+		if err := p.run(ctx, inDevMode(p, mastodonAccount{client}), cal, now); err != nil {
 			return fmt.Errorf("posting to mastodon: %w", err)
 		}
 		return nil
 	})
 	wg.Go(func() error {
-		handle := os.Getenv("BSKY_HANDLE")
-		if handle == "" {
-			log.Println("No BSKY_HANDLE, skipping…")
-			return nil
-		}
-		apiKey := os.Getenv("BSKY_API_KEY")
-		if apiKey == "" {
-			log.Println("No BSKY_API_KEY, skipping…")
-			return nil
-		}
-		ctx := context.Background()
-		client, err := bsky.NewClient(ctx, handle, apiKey)
+		client, err := newBskyClient(ctx)
+		if err != nil || client == nil {
+			return err
+		}
+		p, err := bskyPlatform(tmpls)
 		if err != nil {
-			return fmt.Errorf("creating bsky client: %w", err)
+			return err
 		}
-		if err := postToBsky(context.Background(), client, seasons, now); err != nil {
+		p.ledger = led
+		if err := p.run(ctx, inDevMode(p, bskyAccount{client}), cal, now); err != nil {
 			return fmt.Errorf("posting to bsky: %w", err)
 		}
 		return nil
 	})
-	if err := wg.Wait(); err != nil {
-		log.Fatal(err)
-	}
+	return wg.Wait()
 }
 
-// loadSeasons gets a list of seasons, with dates formatted for the current year.
-func loadSeasons() ([]Season, error) {
-	var rs []rawSeason
-	err := json.Unmarshal([]byte(sekkiJSON), &rs)
-	if err != nil {
-		return nil, fmt.Errorf("error loading sekki: %w", err)
+// newMastodonClient returns a client for the Mastodon account configured in
+// the environment, or nil if there isn't one.
+func newMastodonClient() (*mastodon.Client, error) {
+	baseURL := os.Getenv("MASTODON_BASE_URL")
+	if baseURL == "" {
+		log.Println("No MASTODON_BASE_URL, skipping…")
+		return nil, nil
 	}
-
-	var seasons []Season
-	for _, s := range rs {
-		year := time.Now().Year()
-		hour := "16:02:00" // just so it's not at the beginning of the day
-		dateThisYear, err := time.Parse("2006-01-02 15:04:05", fmt.Sprintf("%d-%s %s", year, s.StartDate, hour))
-		if err != nil {
-			return nil, fmt.Errorf("error parsing date: %w", err)
-		}
-		season := Season{
-			ID:      s.ID,
-			Date:    dateThisYear,
-			Content: fmt.Sprintf("%s. %s %s", s.Title, s.Description, s.Emoji),
-		}
-		seasons = append(seasons, season)
+	accessToken := os.Getenv("MASTODON_ACCESS_TOKEN")
+	if accessToken == "" {
+		log.Println("No MASTODON_ACCESS_TOKEN, skipping…")
+		return nil, nil
 	}
-	return seasons, nil
+	client, err := mastodon.NewClient(mastodon.Config{
+		BaseURL:     baseURL,
+		AccessToken: accessToken,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating mastodon client: %w", err)
+	}
+	return client, nil
 }
 
-// getPostableSeason returns the season that should be posted, or an error if
-// there's nothing to post.
-func getPostableSeason(seasons []Season, now time.Time, latestTimestamps []time.Time) (Season, error) {
-	oneDayAgo := now.Add(time.Hour * -24)
-	oneDayFromNow := now.Add(time.Hour * 24)
-	for _, s := range seasons {
-		if s.Date.Before(oneDayAgo) {
-			// We're running this cron job more frequently than every 24 hours,
-			// so ignore dates before then.
-			continue
-		}
-		if s.Date.After(oneDayFromNow) {
-			// Ignore dates in the future. Our seasons are weeks apart, so this
-			// rough check should never be an issue.
-			continue
-		}
-		for _, t := range latestTimestamps {
-			y1, m1, d1 := t.Date()
-			y2, m2, d2 := s.Date.Date()
-			y3, m3, d3 := now.Date()
-			postedOnDate := y1 == y2 && m1 == m2 && d1 == d2
-			postedToday := y1 == y3 && m1 == m3 && d1 == d3
-			if postedOnDate || postedToday {
-				// If we've already posted on the date, don't post again.
-				return Season{}, ErrAlreadyPosted
-			}
-		}
-		if s.Date.Sub(now) < time.Hour {
-			// If we're within an hour of the expected time, post it!
-			return s, nil
-		}
+// newBskyClient returns a client for the Bluesky account configured in the
+// environment, or nil if there isn't one.
+func newBskyClient(ctx context.Context) (*bsky.Client, error) {
+	handle := os.Getenv("BSKY_HANDLE")
+	if handle == "" {
+		log.Println("No BSKY_HANDLE, skipping…")
+		return nil, nil
 	}
-	return Season{}, ErrNoSeason
+	apiKey := os.Getenv("BSKY_API_KEY")
+	if apiKey == "" {
+		log.Println("No BSKY_API_KEY, skipping…")
+		return nil, nil
+	}
+	client, err := bsky.NewClient(ctx, handle, apiKey)
+	if err != nil {
+		return nil, fmt.Errorf("creating bsky client: %w", err)
+	}
+	return client, nil
 }
 
-func postToBsky(ctx context.Context, client *bsky.Client, seasons []Season, now time.Time) error {
-	posts, err := client.GetPosts(ctx)
+// loadCalendar loads the calendar given by the -calendar flag, with the season
+// data given by the -seasons flag if there is one.
+func loadCalendar(opts ...seasons.Option) (*seasons.Calendar, error) {
+	provider, err := seasons.LookupProvider(*calendar)
 	if err != nil {
-		return fmt.Errorf("getting posts: %w", err)
+		return nil, err
 	}
-	var timestamps []time.Time
-	for _, post := range posts {
-		log.Println("found posts", post.CID, post.AuthorDid, post.AuthorHandle, post.Created)
-		timestamps = append(timestamps, post.Created)
+	if *dataPath == "" {
+		return seasons.FromProvider(provider, opts...)
 	}
-	season, err := getPostableSeason(seasons, now, timestamps)
-	if err != nil {
-		if errors.Is(err, ErrAlreadyPosted) {
-			log.Println("bsky: already posted today")
-			return nil
-		} else if errors.Is(err, ErrNoSeason) {
-			log.Println("bsky: no season to post")
-			return nil
+	opts = append([]seasons.Option{seasons.WithLocation(provider.Location())}, opts...)
+	return seasons.LoadFile(*dataPath, opts...)
+}
+
+// loadTemplates loads the post templates given by flags, the translation
+// catalog and the kigo.
+func loadTemplates() (templates, error) {
+	load := func(path string) (*seasons.Template, error) {
+		if path == "" {
+			return nil, nil
 		}
-		return fmt.Errorf("getting postable season: %w", err)
+		return seasons.ParseTemplateFile(path)
 	}
-	if *dev {
-		log.Printf("bsky: would post %s (skipping in dev mode)", season.ID)
-		return nil
+	var t templates
+	var err error
+	if t.bsky, err = load(*bskyTemplate); err != nil {
+		return templates{}, fmt.Errorf("loading bsky template: %w", err)
 	}
-	log.Printf("bsky: posting %s", season.ID)
-	post, err := bsky.NewPostBuilder().
-		AddText(season.Content).
-		Build()
-	if err != nil {
-		return fmt.Errorf("building post: %w", err)
+	if t.mastodon, err = load(*mastodonTemplate); err != nil {
+		return templates{}, fmt.Errorf("loading mastodon template: %w", err)
 	}
-	_, err = client.PostToFeed(ctx, post)
-	if err != nil {
-		return fmt.Errorf("posting to bsky: %w", err)
+	t.japanese = seasons.MustParseTemplate("japanese", seasons.JapaneseTemplate)
+	if t.catalog, err = seasons.DefaultCatalog(); err != nil {
+		return templates{}, err
 	}
-	return nil
+	if t.saijiki, err = seasons.DefaultSaijiki(); err != nil {
+		return templates{}, err
+	}
+	return t, nil
 }
 
-func postToMastodon(ctx context.Context, client *mastodon.Client, seasons []Season, now time.Time) error {
-	latest, err := client.UserTimeline(ctx)
+// run makes every post due on acct at now: the seasons due, their previews,
+// and the word of the day and the year in review if they're turned on. It's
+// the same for every platform and for simulations, which differ only in acct.
+func (p platform) run(ctx context.Context, acct account, cal *seasons.Calendar, now time.Time) error {
+	var recent []recentPost
+	if p.needsHistory() {
+		var err error
+		if recent, err = acct.since(ctx, p.historySince(now)); err != nil {
+			return fmt.Errorf("getting posts: %w", err)
+		}
+	}
+	h, err := p.readHistory(cal, now, recent)
 	if err != nil {
-		return fmt.Errorf("getting latest toots: %w", err)
+		return err
 	}
-	var timestamps []time.Time
-	for _, toot := range latest {
-		timestamps = append(timestamps, toot.Created)
+	timestamps := p.seasonPostTimes(cal, now, h.seasons)
+	// Keep posting until everything due is out, since several seasons can
+	// fall on the same day.
+	done := make(map[string]bool)
+	for {
+		group, late, err := p.postable(cal, now, h.seasons, done)
+		if err != nil {
+			if errors.Is(err, seasons.ErrAlreadyPosted) {
+				log.Printf("%s: already posted the season due", p.name)
+				break
+			} else if errors.Is(err, seasons.ErrNoSeason) {
+				log.Printf("%s: no season to post", p.name)
+				break
+			}
+			return fmt.Errorf("getting postable season: %w", err)
+		}
+		text, langs, err := p.render(cal, group)
+		if err != nil {
+			return err
+		}
+		if langs[0] != p.locale {
+			log.Printf("%s: %s has no %s translation, posting in English", p.name, describe(group), p.locale)
+		} else if *bilingual && p.locale == seasons.English && len(langs) == 1 {
+			log.Printf("%s: %s is too long to post in both languages, posting in English", p.name, describe(group))
+		}
+		if late {
+			log.Printf("%s: catching up on %s", p.name, describe(group))
+			if text, err = withLate(cal, p, group[0], now, text); err != nil {
+				return err
+			}
+		}
+		text = withHashtags(p, text, group)
+		timestamps = append(timestamps, now)
+		markers := make([]string, len(group))
+		for i, s := range group {
+			markers[i] = s.Marker()
+			done[markers[i]] = true
+		}
+		d := draft{what: describe(group), text: text, langs: langs, markers: markers, kind: ledger.KindSeason, group: group}
+		if *lastYear {
+			prev, ok, err := p.lastYear(ctx, acct, cal, group)
+			if err != nil {
+				return fmt.Errorf("finding last year's post: %w", err)
+			}
+			switch {
+			case !ok:
+				log.Printf("%s: no post from last year for %s", p.name, describe(group))
+			case p.quotes:
+				d.quote = prev
+			default:
+				d.text = withLastYear(p, d.text, prev.url)
+			}
+		}
+		if _, err := p.publish(ctx, acct, d, now); err != nil {
+			return err
+		}
 	}
-	season, err := getPostableSeason(seasons, now, timestamps)
+
+	due, err := duePreviews(cal, p, now, h.previewed)
 	if err != nil {
-		if errors.Is(err, ErrAlreadyPosted) {
-			log.Println("mastodon: already posted today")
-			return nil
-		} else if errors.Is(err, ErrNoSeason) {
-			log.Println("mastodon: no season to post")
-			return nil
-		}
-		return fmt.Errorf("getting postable season: %w", err)
+		return err
 	}
-	if *dev {
-		log.Printf("mastodon: would post %s (skipping in dev mode)", season.ID)
-		return nil
+	for _, pv := range due {
+		d := draft{
+			what:  "preview of " + describe([]seasons.Season{pv.season}),
+			text:  pv.text,
+			langs: []string{pv.lang},
+			kind:  ledger.KindPreview,
+			group: []seasons.Season{pv.season},
+		}
+		if _, err := p.publish(ctx, acct, d, now); err != nil {
+			return err
+		}
 	}
-	log.Printf("mastodon: posting %s", season.ID)
-	status, err := client.PostStatus(ctx, mastodon.PostStatusParams{
-		Status: season.Content,
-	})
-	if err != nil {
-		return fmt.Errorf("posting to mastodon: %w", err)
+
+	if *kigo {
+		text, ok, err := nextKigo(cal, p, p.saijiki, now, timestamps, h.kigo)
+		if err != nil {
+			return err
+		}
+		if ok {
+			d := draft{what: "word of the day", text: text, langs: []string{seasons.English, seasons.Japanese}, tag: seasons.KigoTag, kind: ledger.KindKigo}
+			if _, err := p.publish(ctx, acct, d, now); err != nil {
+				return err
+			}
+		}
 	}
-	log.Printf("mastodon: posted! %s", status.URL)
 
+	if *review {
+		if err := p.postReview(ctx, acct, cal, now); err != nil {
+			return fmt.Errorf("posting review: %w", err)
+		}
+	}
 	return nil
 }
+
+// publish posts d to acct at now, and records it in the ledger. Nothing is
+// recorded for posts that weren't made, in dev mode.
+func (p platform) publish(ctx context.Context, acct account, d draft, now time.Time) (recentPost, error) {
+	log.Printf("%s: posting %s", p.name, d.what)
+	post, err := acct.publish(ctx, d)
+	if err != nil {
+		return recentPost{}, fmt.Errorf("posting %s to %s: %w", d.what, p.name, err)
+	}
+	if post.id == "" {
+		return post, nil
+	}
+	e := post.entry()
+	e.Year, e.Posted = d.year, now
+	if d.kind == ledger.KindKigo {
+		e.Text = d.text
+	}
+	return post, p.record(d.kind, d.group, e)
+}
@@ -0,0 +1,31 @@
+package mastodon
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/rivo/uniseg"
+)
+
+const (
+	// DefaultMaxCharacters is the status length limit of a stock Mastodon
+	// instance.
+	DefaultMaxCharacters = 500
+	// DefaultCharactersPerURL is how many characters each URL in a status
+	// counts as on a stock Mastodon instance, whatever its real length.
+	DefaultCharactersPerURL = 23
+)
+
+var (
+	urlRegex     = regexp.MustCompile(`https?://[^\s]+`)
+	mentionRegex = regexp.MustCompile(`@(\w+)@[\w.-]+\w`)
+)
+
+// StatusLength returns the length of text as Mastodon counts it. URLs count
+// as charactersPerURL characters, remote mentions only count the username,
+// and everything else is counted in grapheme clusters.
+func StatusLength(text string, charactersPerURL int) int {
+	text = urlRegex.ReplaceAllString(text, strings.Repeat("x", charactersPerURL))
+	text = mentionRegex.ReplaceAllString(text, "@$1")
+	return uniseg.GraphemeClusterCount(text)
+}
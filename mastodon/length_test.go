@@ -0,0 +1,19 @@
+package mastodon
+
+import "testing"
+
+func TestStatusLength(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"Rain waters. 🌧", 14},
+		{"More at https://smallseasons.guide/usui/", 8 + DefaultCharactersPerURL},
+		{"Thanks @alice@example.social!", 14},
+	}
+	for _, tt := range tests {
+		if got := StatusLength(tt.text, DefaultCharactersPerURL); got != tt.want {
+			t.Errorf("StatusLength(%q): expected %d, got %d", tt.text, tt.want, got)
+		}
+	}
+}
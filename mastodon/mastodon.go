@@ -47,6 +47,12 @@ type Status struct {
 	Visibility string    `json:"visibility"`
 	Content    string    `json:"content"`
 	Created    time.Time `json:"created_at"`
+	Tags       []Tag     `json:"tags"`
+}
+
+// Tag is a hashtag used in a status.
+type Tag struct {
+	Name string `json:"name"`
 }
 
 // UserTimeline returns the 5 most recent statuses posted by the authenticated user.
@@ -71,21 +77,127 @@ func (c *Client) UserTimeline(ctx context.Context) ([]Status, error) {
 	return statuses, nil
 }
 
+// Account is a Mastodon account.
+type Account struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+}
+
+// VerifyCredentials returns the authenticated user's account.
+func (c *Client) VerifyCredentials(ctx context.Context) (*Account, error) {
+	var account Account
+	if err := c.get(ctx, "/api/v1/accounts/verify_credentials", &account); err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// AccountStatuses returns every status the authenticated user has posted
+// since since, newest first, leaving out boosts. It pages back through the
+// account's statuses as far as needed.
+func (c *Client) AccountStatuses(ctx context.Context, since time.Time) ([]Status, error) {
+	account, err := c.VerifyCredentials(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting account: %w", err)
+	}
+	var all []Status
+	maxID := ""
+	for {
+		v := url.Values{}
+		v.Set("limit", "40")
+		v.Set("exclude_reblogs", "true")
+		if maxID != "" {
+			v.Set("max_id", maxID)
+		}
+		var statuses []Status
+		if err := c.get(ctx, "/api/v1/accounts/"+account.ID+"/statuses?"+v.Encode(), &statuses); err != nil {
+			return nil, err
+		}
+		for _, status := range statuses {
+			if status.Created.Before(since) {
+				return all, nil
+			}
+			all = append(all, status)
+		}
+		if len(statuses) == 0 {
+			return all, nil
+		}
+		maxID = statuses[len(statuses)-1].ID
+	}
+}
+
+// get sends an authenticated GET request for path and decodes the JSON
+// response into v.
+func (c *Client) get(ctx context.Context, path string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	res, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", res.StatusCode)
+	}
+	if err := json.NewDecoder(res.Body).Decode(v); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
+
+// Instance describes a Mastodon instance and its limits.
+type Instance struct {
+	Domain        string `json:"domain"`
+	Configuration struct {
+		Statuses struct {
+			MaxCharacters            int `json:"max_characters"`
+			CharactersReservedPerURL int `json:"characters_reserved_per_url"`
+		} `json:"statuses"`
+	} `json:"configuration"`
+}
+
+// Instance returns information about the Mastodon instance.
+func (c *Client) Instance(ctx context.Context) (*Instance, error) {
+	var instance Instance
+	if err := c.get(ctx, "/api/v2/instance", &instance); err != nil {
+		return nil, err
+	}
+	return &instance, nil
+}
+
 // PostStatusParams are the parameters for posting a new status.
 type PostStatusParams struct {
-	Status string `json:"status"`
+	Status      string `json:"status"`
+	Language    string `json:"language,omitempty"`       // ISO 639 code of the status' language
+	InReplyToID string `json:"in_reply_to_id,omitempty"` // ID of the status being replied to
+
+	// IdempotencyKey stops the same status being posted twice if a request
+	// is retried. Mastodon remembers keys for an hour.
+	IdempotencyKey string `json:"-"`
 }
 
 // PostStatus posts a new status to the authenticated user's account.
 func (c *Client) PostStatus(ctx context.Context, params PostStatusParams) (*Status, error) {
 	v := url.Values{}
 	v.Set("status", params.Status)
+	if params.Language != "" {
+		v.Set("language", params.Language)
+	}
+	if params.InReplyToID != "" {
+		v.Set("in_reply_to_id", params.InReplyToID)
+	}
 	url := fmt.Sprintf("%s/api/v1/statuses?%s", c.baseURL, v.Encode())
 	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("creating request: %w", err)
 	}
 	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	if params.IdempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", params.IdempotencyKey)
+	}
 	res, err := c.http.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("sending request: %w", err)
@@ -2,8 +2,12 @@ package mastodon
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestUserTimeline(t *testing.T) {
@@ -20,3 +24,41 @@ func TestUserTimeline(t *testing.T) {
 	}
 	t.Logf("statuses: %v", statuses)
 }
+
+func TestAccountStatuses(t *testing.T) {
+	day := func(d int) string {
+		return time.Date(2026, time.December, d, 7, 2, 0, 0, time.UTC).Format(time.RFC3339)
+	}
+	pages := map[string]string{
+		"":  `[{"id":"5","created_at":"` + day(30) + `"},{"id":"4","created_at":"` + day(20) + `"}]`,
+		"4": `[{"id":"3","created_at":"` + day(10) + `"},{"id":"2","created_at":"` + day(1) + `"}]`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/accounts/verify_credentials" {
+			w.Write([]byte(`{"id":"1","username":"smallseasons"}`))
+			return
+		}
+		if r.URL.Path != "/api/v1/accounts/1/statuses" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(pages[r.URL.Query().Get("max_id")]))
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(Config{BaseURL: srv.URL, AccessToken: "token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	statuses, err := client.AccountStatuses(context.Background(), time.Date(2026, time.December, 5, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, s := range statuses {
+		ids = append(ids, s.ID)
+	}
+	if got := strings.Join(ids, ","); got != "5,4,3" {
+		t.Errorf("expected statuses 5,4,3, got %s", got)
+	}
+}
@@ -0,0 +1,23 @@
+package mastodon
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	paragraphRegex = regexp.MustCompile(`</p>\s*<p>`)
+	breakRegex     = regexp.MustCompile(`<br\s*/?>`)
+	tagRegex       = regexp.MustCompile(`<[^>]*>`)
+)
+
+// Text returns the status's content as plain text, as it was posted.
+// Mastodon stores statuses as HTML, with paragraphs for blank lines and
+// links around hashtags and URLs.
+func (s Status) Text() string {
+	text := paragraphRegex.ReplaceAllString(s.Content, "\n\n")
+	text = breakRegex.ReplaceAllString(text, "\n")
+	text = tagRegex.ReplaceAllString(text, "")
+	return strings.TrimSpace(html.UnescapeString(text))
+}
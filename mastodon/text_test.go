@@ -0,0 +1,24 @@
+package mastodon
+
+import "testing"
+
+func TestStatusText(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"plain", "<p>Rain waters 🌧</p>", "Rain waters 🌧"},
+		{"paragraphs", "<p>First</p><p>Second</p>", "First\n\nSecond"},
+		{"line breaks", "<p>First<br />Second</p>", "First\nSecond"},
+		{"entities", "<p>Bees &amp; wasps don&#39;t sleep</p>", "Bees & wasps don't sleep"},
+		{"hashtags", `<p>梅 <a href="https://mastodon.social/tags/kigo" class="mention hashtag" rel="tag">#<span>kigo</span></a></p>`, "梅 #kigo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (Status{Content: tt.content}).Text(); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
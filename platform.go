@@ -0,0 +1,422 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rosszurowski/small-seasons-bot/bsky"
+	"github.com/rosszurowski/small-seasons-bot/ledger"
+	"github.com/rosszurowski/small-seasons-bot/mastodon"
+	"github.com/rosszurowski/small-seasons-bot/seasons"
+)
+
+// platform describes how a platform measures the length of a post, the locale
+// its account posts in, and the templates posts to it are rendered with.
+type platform struct {
+	name      string
+	maxLength int
+	length    func(text string) int
+	locale    string
+	catalog   *seasons.Catalog
+	template  *seasons.Template
+	english   *seasons.Template // template for seasons without a translation
+	japanese  *seasons.Template // Japanese text added to bilingual posts
+	saijiki   *seasons.Saijiki  // kigo for word of the day posts
+	ledger    *ledger.Ledger    // record of posts, or nil to go by recent posts
+	hashtags  bool              // posts carry season markers as hashtags, having nowhere else to keep them
+	quotes    bool              // posts can quote other posts, rather than linking to them
+
+	previewDays int             // days ahead to announce each season, or 0 for none
+	catchUp     seasons.CatchUp // what to do with seasons missed while the bot was down
+}
+
+// bskyPlatform returns the rules for posting to Bluesky, configured by the
+// BSKY_ environment variables.
+func bskyPlatform(tmpls templates) (platform, error) {
+	return platform{
+		name:      "bsky",
+		maxLength: bsky.MaxPostLength,
+		length:    bsky.PostLength,
+		japanese:  tmpls.japanese,
+		quotes:    true,
+	}.configure(tmpls, tmpls.bsky, "BSKY")
+}
+
+// mastodonPlatform returns the rules for posting to Mastodon, configured by the
+// MASTODON_ environment variables. The limits come from client's instance,
+// since they vary between servers, or the defaults if client is nil.
+func mastodonPlatform(ctx context.Context, client *mastodon.Client, tmpls templates) (platform, error) {
+	maxChars, perURL := mastodon.DefaultMaxCharacters, mastodon.DefaultCharactersPerURL
+	if client != nil {
+		instance, err := client.Instance(ctx)
+		if err != nil {
+			return platform{}, fmt.Errorf("getting mastodon instance: %w", err)
+		}
+		if n := instance.Configuration.Statuses.MaxCharacters; n > 0 {
+			maxChars = n
+		}
+		if n := instance.Configuration.Statuses.CharactersReservedPerURL; n > 0 {
+			perURL = n
+		}
+	} else {
+		log.Println("mastodon: using default limits")
+	}
+	return platform{
+		name:      "mastodon",
+		maxLength: maxChars,
+		length: func(text string) int {
+			return mastodon.StatusLength(text, perURL)
+		},
+		japanese: tmpls.japanese,
+		hashtags: true,
+	}.configure(tmpls, tmpls.mastodon, "MASTODON")
+}
+
+// configure sets up the platform's account from the environment variables
+// starting with prefix: its locale from _LOCALE, defaulting to English, how
+// many days ahead to announce seasons from _PREVIEW_DAYS, and its catch-up
+// policy from _CATCH_UP, defaulting to the -catch-up flag. Without a template
+// of its own, a platform gets the default one for its locale.
+func (p platform) configure(tmpls templates, tmpl *seasons.Template, prefix string) (platform, error) {
+	p.locale = os.Getenv(prefix + "_LOCALE")
+	if p.locale == "" {
+		p.locale = seasons.English
+	}
+	if !tmpls.catalog.HasLocale(p.locale) {
+		return platform{}, fmt.Errorf("unknown %s_LOCALE %q: must be one of %s", prefix, p.locale, strings.Join(tmpls.catalog.Locales(), ", "))
+	}
+	var err error
+	if p.previewDays, err = previewDays(prefix); err != nil {
+		return platform{}, err
+	}
+	policy := os.Getenv(prefix + "_CATCH_UP")
+	if policy == "" {
+		policy = *catchUp
+	}
+	if p.catchUp, err = seasons.ParseCatchUp(policy); err != nil {
+		return platform{}, fmt.Errorf("%s_CATCH_UP: %w", prefix, err)
+	}
+	p.catalog = tmpls.catalog
+	p.saijiki = tmpls.saijiki
+	p.english = tmpl
+	if p.english == nil && *almanac {
+		p.english = seasons.MustParseTemplate("almanac", seasons.AlmanacTemplate)
+	} else if p.english == nil {
+		p.english = seasons.MustParseTemplate("default", seasons.DefaultTemplate)
+	}
+	p.template = p.english
+	if tmpl == nil && p.locale == seasons.Japanese {
+		// The Japanese title already carries its reading, so the emoji is all
+		// the default template needs on top.
+		p.template = seasons.MustParseTemplate("japanese", seasons.JapaneseTemplate+" {{.Emoji}}")
+	} else if tmpl == nil && p.locale == seasons.Chinese {
+		p.template = seasons.MustParseTemplate("chinese", seasons.ChineseTemplate)
+	}
+	return p, nil
+}
+
+// previewDays returns how many days ahead the platform configured by the
+// environment variables starting with prefix announces seasons, from
+// _PREVIEW_DAYS, or 0 for none.
+func previewDays(prefix string) (int, error) {
+	days := os.Getenv(prefix + "_PREVIEW_DAYS")
+	if days == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(days)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s_PREVIEW_DAYS %q: must be a number of days", prefix, days)
+	}
+	return n, nil
+}
+
+// platforms returns the rules for every platform, using the configured
+// Mastodon instance's limits if there is one.
+func platforms(ctx context.Context, tmpls templates) ([]platform, error) {
+	client, err := newMastodonClient()
+	if err != nil {
+		return nil, err
+	}
+	m, err := mastodonPlatform(ctx, client, tmpls)
+	if err != nil {
+		return nil, err
+	}
+	b, err := bskyPlatform(tmpls)
+	if err != nil {
+		return nil, err
+	}
+	return []platform{b, m}, nil
+}
+
+// render renders the text of a post for a group of seasons posted together,
+// one paragraph per season, and returns it along with the languages it's
+// written in. The post is in the platform's locale, unless a season in the
+// group hasn't been translated into it, in which case the whole post is left
+// in English rather than mixing languages.
+func (p platform) render(cal *seasons.Calendar, group []seasons.Season) (string, []string, error) {
+	if p.locale == seasons.English {
+		return p.renderEnglish(cal, group)
+	}
+	var paragraphs []string
+	for _, s := range group {
+		data, ok := p.catalog.TranslateData(cal.Data(s), p.locale)
+		if !ok {
+			return p.renderEnglish(cal, group)
+		}
+		text, err := p.template.Execute(data)
+		if err != nil {
+			return "", nil, fmt.Errorf("rendering %s in %s: %w", s.ID, p.locale, err)
+		}
+		paragraphs = append(paragraphs, text)
+	}
+	return strings.Join(paragraphs, "\n\n"), []string{p.locale}, nil
+}
+
+// renderEnglish renders a post in English. In bilingual mode each season's
+// Japanese text follows its English, unless that would take the post over the
+// platform's limit, in which case the post is left in English.
+func (p platform) renderEnglish(cal *seasons.Calendar, group []seasons.Season) (string, []string, error) {
+	var english, both []string
+	hasJapanese := false
+	for _, s := range group {
+		text, err := p.english.Render(cal, s)
+		if err != nil {
+			return "", nil, fmt.Errorf("rendering %s: %w", s.ID, err)
+		}
+		english = append(english, text)
+		if *bilingual && s.JapaneseDescription != "" {
+			ja, err := p.japanese.Render(cal, s)
+			if err != nil {
+				return "", nil, fmt.Errorf("rendering %s in Japanese: %w", s.ID, err)
+			}
+			text += "\n\n" + ja
+			hasJapanese = true
+		}
+		both = append(both, text)
+	}
+	if hasJapanese {
+		if text := strings.Join(both, "\n\n"); p.length(text) <= p.maxLength {
+			return text, []string{seasons.English, seasons.Japanese}, nil
+		}
+	}
+	return strings.Join(english, "\n\n"), []string{seasons.English}, nil
+}
+
+// recentPost is one of an account's recent posts, as used to tell which
+// seasons it has already announced.
+type recentPost struct {
+	id      string // status ID on Mastodon, record URI on Bluesky
+	cid     string // content ID of Bluesky records
+	url     string
+	text    string
+	markers []string // season markers on the post, where the platform keeps them
+	created time.Time
+}
+
+// entry returns the ledger entry for the post.
+func (r recentPost) entry() ledger.Entry {
+	return ledger.Entry{ID: r.id, CID: r.cid, URL: r.url, Posted: r.created}
+}
+
+// fromEntry returns the post a ledger entry records.
+func fromEntry(e ledger.Entry) recentPost {
+	return recentPost{id: e.ID, cid: e.CID, url: e.URL, created: e.Posted}
+}
+
+// fromBsky returns a Bluesky post as a recent post.
+func fromBsky(post *bsky.BlueskyPost) recentPost {
+	return recentPost{id: post.URI, cid: post.CID, text: post.Text, markers: post.Tags, created: post.Created}
+}
+
+// fromMastodon returns a status as a recent post. Statuses can't carry hidden
+// markers, so its hashtags stand in for them.
+func fromMastodon(status mastodon.Status) recentPost {
+	tags := make([]string, len(status.Tags))
+	for i, t := range status.Tags {
+		tags[i] = strings.ToLower(t.Name)
+	}
+	return recentPost{id: status.ID, url: status.URL, text: status.Text(), markers: tags, created: status.Created}
+}
+
+// history is what an account has posted lately, as used to tell what's due.
+type history struct {
+	seasons   []recentPost         // season posts
+	kigo      map[time.Time]string // word of the day posts, by when they went out
+	previewed map[string]bool      // text of the previews posted
+}
+
+// needsHistory reports whether p has to read back its account's recent posts
+// to tell what's been posted: when it keeps no ledger, or while the ledger
+// has nothing on record for it yet. What's found then is recorded, so the
+// ledger takes over from there.
+func (p platform) needsHistory() bool {
+	return len(p.ledger.Entries(p.name)) == 0
+}
+
+// historySince returns how far back to read p's recent posts from at now. A
+// platform that catches up on missed seasons reads back a year, so the last
+// season posted before the bot went down turns up however long it was down.
+func (p platform) historySince(now time.Time) time.Time {
+	if p.catchUp != seasons.CatchUpSkip {
+		return now.AddDate(-1, 0, 0)
+	}
+	return now.AddDate(0, 0, -historyDays)
+}
+
+// readHistory sorts posts, the account's recent posts if it needs them, into
+// season posts, words of the day and previews, and adds the words of the day
+// on record in the ledger. Words of the day and previews found among posts
+// are recorded in the ledger to start it off; season posts are recorded as
+// postable comes across them.
+func (p platform) readHistory(cal *seasons.Calendar, now time.Time, posts []recentPost) (history, error) {
+	h := history{kigo: make(map[time.Time]string), previewed: make(map[string]bool)}
+	for _, e := range p.ledger.Entries(p.name) {
+		if e.Kind == ledger.KindKigo {
+			h.kigo[e.Posted] = e.Text
+		}
+	}
+	if len(posts) == 0 {
+		return h, nil
+	}
+	previews, err := previewTexts(cal, p, now)
+	if err != nil {
+		return history{}, err
+	}
+	for _, post := range posts {
+		if s, ok := previews[post.text]; ok {
+			h.previewed[post.text] = true
+			if err := p.record(ledger.KindPreview, []seasons.Season{s}, post.entry()); err != nil {
+				return history{}, err
+			}
+		} else if strings.Contains(post.text, "#"+seasons.KigoTag) || slices.Contains(post.markers, seasons.KigoTag) {
+			h.kigo[post.created] = post.text
+			e := post.entry()
+			e.Text = post.text
+			if err := p.record(ledger.KindKigo, nil, e); err != nil {
+				return history{}, err
+			}
+		} else {
+			h.seasons = append(h.seasons, post)
+		}
+	}
+	return h, nil
+}
+
+// postable returns the seasons to post next at now, and whether they're being
+// posted late. Seasons already posted are left out: those in done, on record
+// in the ledger, or failing that, announced by one of recent, which is then
+// recorded in the ledger. Seasons missed while the bot was down come first,
+// if the platform catches up on them.
+func (p platform) postable(cal *seasons.Calendar, now time.Time, recent []recentPost, done map[string]bool) ([]seasons.Season, bool, error) {
+	posted := func(s seasons.Season) bool {
+		if done[s.Marker()] {
+			return true
+		}
+		if _, ok := p.ledger.Find(p.name, ledger.KindSeason, s.ID, s.Date.Year()); ok {
+			return true
+		}
+		post, ok := p.announced(cal, s, recent)
+		if ok {
+			if err := p.record(ledger.KindSeason, []seasons.Season{s}, post.entry()); err != nil {
+				log.Printf("%s: %v", p.name, err)
+			}
+		}
+		return ok
+	}
+	if s, ok := p.missed(cal, now, posted); ok {
+		return []seasons.Season{s}, true, nil
+	}
+	group, err := cal.Unposted(now, posted)
+	return group, false, err
+}
+
+// announced returns the post among recent that announced s. A post announced
+// s if it carries the season's marker, or its hashtag on platforms that keep
+// markers in the text. Failing that, for posts made before markers were added
+// or without room for one, it announced s if it contains the text s is posted
+// with and went out in the year after s was due, since the text can be the
+// same from one year to the next.
+func (p platform) announced(cal *seasons.Calendar, s seasons.Season, recent []recentPost) (recentPost, bool) {
+	marker, hashtag := s.Marker(), s.Hashtag()
+	for _, post := range recent {
+		if slices.Contains(post.markers, marker) || slices.Contains(post.markers, hashtag) {
+			return post, true
+		}
+	}
+	text, _, err := p.render(cal, []seasons.Season{s})
+	if err != nil {
+		return recentPost{}, false
+	}
+	from, to := s.Date.AddDate(0, 0, -1), s.Date.AddDate(1, 0, -1)
+	for _, post := range recent {
+		if strings.Contains(post.text, text) && post.created.After(from) && post.created.Before(to) {
+			return post, true
+		}
+	}
+	return recentPost{}, false
+}
+
+// withHashtags adds the hashtags of a group's markers to the end of text, on
+// platforms that keep markers as hashtags. They're left off if there isn't
+// room for them.
+func withHashtags(p platform, text string, group []seasons.Season) string {
+	if !p.hashtags {
+		return text
+	}
+	tags := make([]string, len(group))
+	for i, s := range group {
+		tags[i] = "#" + s.Hashtag()
+	}
+	tagged := text + "\n\n" + strings.Join(tags, " ")
+	if p.length(tagged) > p.maxLength {
+		log.Printf("%s: no room to tag %s", p.name, describe(group))
+		return text
+	}
+	return tagged
+}
+
+// seasonPostTimes returns the times of the recent season posts on record in
+// the ledger or among recent.
+func (p platform) seasonPostTimes(cal *seasons.Calendar, now time.Time, recent []recentPost) []time.Time {
+	var times []time.Time
+	for _, s := range cal.InRange(now.AddDate(0, 0, -historyDays), now.AddDate(0, 0, 1)) {
+		if e, ok := p.ledger.Find(p.name, ledger.KindSeason, s.ID, s.Date.Year()); ok {
+			times = append(times, e.Posted)
+		} else if post, ok := p.announced(cal, s, recent); ok {
+			times = append(times, post.created)
+		}
+	}
+	return times
+}
+
+// record records a post of the given kind in the ledger, with an entry for
+// each season in group, or a single entry if it isn't about any season.
+func (p platform) record(kind string, group []seasons.Season, e ledger.Entry) error {
+	e.Platform, e.Kind = p.name, kind
+	if len(group) == 0 {
+		return p.ledger.Record(e)
+	}
+	for _, s := range group {
+		e.Season, e.Year = s.ID, s.Date.Year()
+		if err := p.ledger.Record(e); err != nil {
+			return fmt.Errorf("recording %s: %w", s.ID, err)
+		}
+	}
+	return nil
+}
+
+// describe returns a description of a group of seasons for logging, like
+// "sekki rikka, sekku tango-no-sekku".
+func describe(group []seasons.Season) string {
+	names := make([]string, len(group))
+	for i, s := range group {
+		names[i] = fmt.Sprintf("%s %s", s.Kind, s.ID)
+	}
+	return strings.Join(names, ", ")
+}
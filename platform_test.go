@@ -0,0 +1,451 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/rosszurowski/small-seasons-bot/ledger"
+	"github.com/rosszurowski/small-seasons-bot/seasons"
+)
+
+// testPlatform returns a platform posting in locale, that allows posts of up
+// to maxLength characters.
+func testPlatform(t *testing.T, locale string, maxLength int) platform {
+	t.Helper()
+	tmpls, err := loadTemplates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TEST_LOCALE", locale)
+	p, err := platform{
+		name:      "test",
+		maxLength: maxLength,
+		length:    utf8.RuneCountInString,
+		japanese:  tmpls.japanese,
+	}.configure(tmpls, nil, "TEST")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+// withBilingual runs the rest of the test with the -bilingual flag set to on.
+func withBilingual(t *testing.T, on bool) {
+	t.Helper()
+	was := *bilingual
+	*bilingual = on
+	t.Cleanup(func() { *bilingual = was })
+}
+
+func TestRender(t *testing.T) {
+	cal, err := seasons.Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	usui, ok := cal.Only(seasons.KindSekki).Current(time.Date(2026, time.February, 20, 0, 0, 0, 0, cal.PostTime().Location))
+	if !ok {
+		t.Fatal("expected a season")
+	}
+	english := "Rain waters. Snow melts away"
+	japanese := "雨水（うすい）"
+
+	for _, tt := range []struct {
+		name      string
+		bilingual bool
+		maxLength int
+		wantLangs []string
+		want      []string
+		dontWant  []string
+	}{
+		{"posts in English", false, 1000, []string{"en"}, []string{english}, []string{japanese}},
+		{"adds the Japanese when bilingual", true, 1000, []string{"en", "ja"}, []string{english, japanese}, nil},
+		{"leaves out the Japanese when it doesn't fit", true, 160, []string{"en"}, []string{english}, []string{japanese}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			withBilingual(t, tt.bilingual)
+			p := testPlatform(t, seasons.English, tt.maxLength)
+			text, langs, err := p.render(cal, []seasons.Season{usui})
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if !slices.Equal(langs, tt.wantLangs) {
+				t.Errorf("expected languages %v, got %v", tt.wantLangs, langs)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(text, want) {
+					t.Errorf("expected %q in %q", want, text)
+				}
+			}
+			for _, dontWant := range tt.dontWant {
+				if strings.Contains(text, dontWant) {
+					t.Errorf("expected no %q in %q", dontWant, text)
+				}
+			}
+			if n := p.length(text); n > tt.maxLength {
+				t.Errorf("expected at most %d characters, got %d", tt.maxLength, n)
+			}
+		})
+	}
+
+	t.Run("posts in the platform's locale", func(t *testing.T) {
+		withBilingual(t, false)
+		p := testPlatform(t, "de", 1000)
+		text, langs, err := p.render(cal, []seasons.Season{usui})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !slices.Equal(langs, []string{"de"}) {
+			t.Errorf("expected languages [de], got %v", langs)
+		}
+		if strings.Contains(text, english) {
+			t.Errorf("expected German, got %q", text)
+		}
+	})
+
+	t.Run("falls back to English without a translation", func(t *testing.T) {
+		withBilingual(t, false)
+		jieqi, err := seasons.FromProvider(seasons.Jieqi)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lichun, ok := jieqi.Current(time.Date(2026, time.February, 10, 0, 0, 0, 0, time.UTC))
+		if !ok {
+			t.Fatal("expected a season")
+		}
+		p := testPlatform(t, "de", 1000)
+		text, langs, err := p.render(jieqi, []seasons.Season{lichun})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !slices.Equal(langs, []string{"en"}) {
+			t.Errorf("expected languages [en], got %v", langs)
+		}
+		if !strings.HasPrefix(text, "Start of spring") {
+			t.Errorf("expected English, got %q", text)
+		}
+	})
+
+	t.Run("posts other calendars in their own language", func(t *testing.T) {
+		withBilingual(t, false)
+		jieqi, err := seasons.FromProvider(seasons.Jieqi)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lichun, ok := jieqi.Current(time.Date(2026, time.February, 10, 0, 0, 0, 0, time.UTC))
+		if !ok {
+			t.Fatal("expected a season")
+		}
+		p := testPlatform(t, seasons.Chinese, 1000)
+		text, langs, err := p.render(jieqi, []seasons.Season{lichun})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !slices.Equal(langs, []string{seasons.Chinese}) {
+			t.Errorf("expected languages [zh], got %v", langs)
+		}
+		if !strings.HasPrefix(text, "立春：东风解冻") {
+			t.Errorf("expected Chinese, got %q", text)
+		}
+	})
+}
+
+func TestAnnounced(t *testing.T) {
+	cal, err := seasons.Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	usui, ok := cal.Only(seasons.KindSekki).Current(time.Date(2026, time.February, 20, 0, 0, 0, 0, cal.PostTime().Location))
+	if !ok {
+		t.Fatal("expected a season")
+	}
+	p := testPlatform(t, seasons.English, 1000)
+	text, _, err := p.render(cal, []seasons.Season{usui})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name string
+		post recentPost
+		want bool
+	}{
+		{"finds posts by their marker", recentPost{text: "edited", markers: []string{usui.Marker()}, created: usui.Date}, true},
+		{"finds posts by their hashtag", recentPost{text: "edited", markers: []string{usui.Hashtag()}, created: usui.Date}, true},
+		{"falls back to the text", recentPost{text: text, created: usui.Date}, true},
+		{"ignores other seasons' markers", recentPost{text: "edited", markers: []string{"sekki:usui:2025"}, created: usui.Date}, false},
+		{"ignores the same text from last year", recentPost{text: text, created: usui.Date.AddDate(-1, 0, 0)}, false},
+		{"ignores the same text posted early", recentPost{text: text, created: usui.Date.AddDate(0, 0, -2)}, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, got := p.announced(cal, usui, []recentPost{tt.post}); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPostable(t *testing.T) {
+	cal, err := seasons.Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Usui and its first kō both start on February 19th 2026.
+	usui, ok := cal.Only(seasons.KindSekki).Current(time.Date(2026, time.February, 20, 0, 0, 0, 0, cal.PostTime().Location))
+	if !ok {
+		t.Fatal("expected a season")
+	}
+	now := usui.Date
+	p := testPlatform(t, seasons.English, 1000)
+
+	t.Run("posts the season due", func(t *testing.T) {
+		group, late, err := p.postable(cal, now, nil, nil)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(group) != 1 || group[0].ID != "usui" || late {
+			t.Errorf("expected usui on time, got %v (late %v)", group, late)
+		}
+	})
+
+	t.Run("moves on past seasons already posted", func(t *testing.T) {
+		recent := []recentPost{{text: "edited", markers: []string{usui.Marker()}, created: now}}
+		group, _, err := p.postable(cal, now, recent, nil)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(group) != 1 || group[0].Kind != seasons.KindKo {
+			t.Errorf("expected usui's first kō, got %v", group)
+		}
+	})
+
+	t.Run("stops once everything due is done", func(t *testing.T) {
+		done := make(map[string]bool)
+		for {
+			group, _, err := p.postable(cal, now, nil, done)
+			if errors.Is(err, seasons.ErrAlreadyPosted) {
+				break
+			} else if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if len(done) > 2 {
+				t.Fatalf("expected two posts, got %v", done)
+			}
+			done[group[0].Marker()] = true
+		}
+		if len(done) != 2 {
+			t.Errorf("expected two posts, got %v", done)
+		}
+	})
+}
+
+func TestWithHashtags(t *testing.T) {
+	cal, err := seasons.Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	usui, ok := cal.Only(seasons.KindSekki).Current(time.Date(2026, time.February, 20, 0, 0, 0, 0, cal.PostTime().Location))
+	if !ok {
+		t.Fatal("expected a season")
+	}
+
+	t.Run("tags posts with their markers", func(t *testing.T) {
+		p := testPlatform(t, seasons.English, 1000)
+		p.hashtags = true
+		if got, want := withHashtags(p, "Rain waters.", []seasons.Season{usui}), "Rain waters.\n\n#sekki_usui_2026"; got != want {
+			t.Errorf("expected %q, got %q", want, got)
+		}
+	})
+
+	t.Run("leaves the tags off when they don't fit", func(t *testing.T) {
+		p := testPlatform(t, seasons.English, 20)
+		p.hashtags = true
+		if got := withHashtags(p, "Rain waters.", []seasons.Season{usui}); got != "Rain waters." {
+			t.Errorf("expected no tags, got %q", got)
+		}
+	})
+}
+
+func TestDuePreviews(t *testing.T) {
+	cal, err := seasons.Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	usui, ok := cal.Only(seasons.KindSekki).Current(time.Date(2026, time.February, 20, 0, 0, 0, 0, cal.PostTime().Location))
+	if !ok {
+		t.Fatal("expected a season")
+	}
+	at := usui.Date.AddDate(0, 0, -3)
+
+	for _, tt := range []struct {
+		locale string
+		want   string
+	}{
+		{seasons.English, "In three days: Rain waters 🌧"},
+		{"de", "In 3 Tagen: Regenwasser 🌧"},
+		{seasons.Japanese, "3日後：雨水 🌧"},
+	} {
+		t.Run("previews in "+tt.locale, func(t *testing.T) {
+			p := testPlatform(t, tt.locale, 1000)
+			p.previewDays = 3
+			due, err := duePreviews(cal, p, at, nil)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if len(due) != 1 || due[0].text != tt.want || due[0].lang != tt.locale {
+				t.Errorf("expected %q in %s, got %v", tt.want, tt.locale, due)
+			}
+		})
+	}
+
+	t.Run("falls back to English without a translation", func(t *testing.T) {
+		jieqi, err := seasons.FromProvider(seasons.Jieqi)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lichun, ok := jieqi.Current(time.Date(2026, time.February, 10, 0, 0, 0, 0, time.UTC))
+		if !ok {
+			t.Fatal("expected a season")
+		}
+		p := testPlatform(t, "de", 1000)
+		p.previewDays = 1
+		due, err := duePreviews(jieqi, p, lichun.Date.AddDate(0, 0, -1), nil)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(due) != 1 || !strings.HasPrefix(due[0].text, "Tomorrow: Start of spring") || due[0].lang != seasons.English {
+			t.Errorf("expected an English preview, got %v", due)
+		}
+	})
+}
+
+func TestLedger(t *testing.T) {
+	cal, err := seasons.Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	usui, ok := cal.Only(seasons.KindSekki).Current(time.Date(2026, time.February, 20, 0, 0, 0, 0, cal.PostTime().Location))
+	if !ok {
+		t.Fatal("expected a season")
+	}
+	now := usui.Date
+	withLedger := func(t *testing.T) platform {
+		t.Helper()
+		led, err := ledger.Open(filepath.Join(t.TempDir(), "ledger.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := testPlatform(t, seasons.English, 1000)
+		p.ledger = led
+		return p
+	}
+
+	t.Run("starts off from recent posts", func(t *testing.T) {
+		p := withLedger(t)
+		if !p.needsHistory() {
+			t.Fatal("expected an empty ledger to need recent posts")
+		}
+		recent := []recentPost{{id: "1", text: "edited", markers: []string{usui.Marker()}, created: now}}
+		if _, _, err := p.postable(cal, now, recent, nil); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		e, ok := p.ledger.Find(p.name, ledger.KindSeason, usui.ID, usui.Date.Year())
+		if !ok || e.ID != "1" {
+			t.Errorf("expected usui on record as 1, got %v", e)
+		}
+		if p.needsHistory() {
+			t.Error("expected the ledger to take over")
+		}
+	})
+
+	t.Run("goes by the ledger first", func(t *testing.T) {
+		p := withLedger(t)
+		if err := p.record(ledger.KindSeason, []seasons.Season{usui}, ledger.Entry{ID: "1", Posted: now}); err != nil {
+			t.Fatal(err)
+		}
+		group, _, err := p.postable(cal, now, nil, nil)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(group) != 1 || group[0].Kind != seasons.KindKo {
+			t.Errorf("expected usui's first kō, got %v", group)
+		}
+	})
+
+	t.Run("reads words of the day from the ledger", func(t *testing.T) {
+		p := withLedger(t)
+		if err := p.record(ledger.KindKigo, nil, ledger.Entry{ID: "1", Text: "Haru-same", Posted: now}); err != nil {
+			t.Fatal(err)
+		}
+		h, err := p.readHistory(cal, now, nil)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if h.kigo[now] != "Haru-same" {
+			t.Errorf("expected Haru-same, got %v", h.kigo)
+		}
+	})
+
+	t.Run("plans the review from the ledger", func(t *testing.T) {
+		p := withLedger(t)
+		eve := time.Date(2026, time.December, 31, 16, 2, 0, 0, cal.PostTime().Location)
+		_, all, ok := cal.Review(eve)
+		if !ok {
+			t.Fatal("expected a review")
+		}
+		for i, s := range all[1:] {
+			if err := p.record(ledger.KindSeason, []seasons.Season{s}, ledger.Entry{ID: fmt.Sprint(i), Posted: s.Date}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		fetched := 0
+		fetch := func() ([]recentPost, error) {
+			fetched++
+			return []recentPost{{id: "first", markers: []string{all[0].Marker()}, created: all[0].Date}}, nil
+		}
+		_, entries, ok, err := planReview(cal, p, eve, fetch)
+		if err != nil || !ok {
+			t.Fatalf("expected a review, got %v", err)
+		}
+		if len(entries) != len(all) || entries[0].post.id != "first" || entries[1].post.id != "0" {
+			t.Errorf("expected every season's post, got %v", entries)
+		}
+		if fetched != 1 {
+			t.Errorf("expected posts fetched once for the season missing from the ledger, got %d", fetched)
+		}
+	})
+
+	t.Run("records previews found among recent posts", func(t *testing.T) {
+		p := withLedger(t)
+		p.previewDays = 3
+		at := usui.Date.AddDate(0, 0, -3)
+		text, _, err := renderPreview(p, usui)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h, err := p.readHistory(cal, at, []recentPost{{id: "1", text: text, created: at}})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !h.previewed[text] || len(h.seasons) != 0 {
+			t.Errorf("expected the preview to be told apart, got %v", h)
+		}
+		if _, ok := p.ledger.Find(p.name, ledger.KindPreview, usui.ID, usui.Date.Year()); !ok {
+			t.Error("expected the preview on record")
+		}
+		due, err := duePreviews(cal, p, at, nil)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		for _, pv := range due {
+			if pv.season.ID == usui.ID {
+				t.Errorf("expected usui's preview to be done, got %q", pv.text)
+			}
+		}
+	})
+}
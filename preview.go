@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/rosszurowski/small-seasons-bot/ledger"
+	"github.com/rosszurowski/small-seasons-bot/seasons"
+)
+
+// previewTemplates render posts announcing a season ahead of time, by locale.
+var previewTemplates = func() map[string]*seasons.Template {
+	tmpls := make(map[string]*seasons.Template)
+	for locale, text := range seasons.PreviewTemplates {
+		tmpls[locale] = seasons.MustParseTemplate("preview-"+locale, text)
+	}
+	return tmpls
+}()
+
+// renderPreview renders the post announcing s p.previewDays ahead of time,
+// and returns it along with the language it's written in. It's in p's
+// locale, or in English if s hasn't been translated into it.
+func renderPreview(p platform, s seasons.Season) (string, string, error) {
+	locale := seasons.English
+	if _, ok := previewTemplates[p.locale]; ok && p.locale != seasons.English {
+		if t, ok := p.catalog.Translate(s, p.locale); ok {
+			locale, s = p.locale, t
+		}
+	}
+	text, err := previewTemplates[locale].Execute(seasons.PreviewData{Season: s, Days: p.previewDays})
+	if err != nil {
+		return "", "", fmt.Errorf("rendering preview of %s: %w", s.ID, err)
+	}
+	return text, locale, nil
+}
+
+// previewTexts returns the text of every preview p may have posted recently,
+// along with the season each announces, so they can be told apart from
+// season posts.
+func previewTexts(cal *seasons.Calendar, p platform, now time.Time) (map[string]seasons.Season, error) {
+	texts := make(map[string]seasons.Season)
+	for _, s := range cal.Previewed(now, p.previewDays) {
+		text, _, err := renderPreview(p, s)
+		if err != nil {
+			return nil, err
+		}
+		texts[text] = s
+	}
+	return texts, nil
+}
+
+// preview is a post announcing a season ahead of time.
+type preview struct {
+	season seasons.Season
+	text   string
+	lang   string
+}
+
+// duePreviews returns the previews p should post at now, leaving out any on
+// record in the ledger or among its recent posts.
+func duePreviews(cal *seasons.Calendar, p platform, now time.Time, posted map[string]bool) ([]preview, error) {
+	var due []preview
+	for _, s := range cal.Previews(now, p.previewDays) {
+		text, lang, err := renderPreview(p, s)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := p.ledger.Find(p.name, ledger.KindPreview, s.ID, s.Date.Year()); ok || posted[text] {
+			log.Printf("%s: already previewed %s %s", p.name, s.Kind, s.ID)
+			continue
+		}
+		if lang != p.locale {
+			log.Printf("%s: %s %s has no %s translation, previewing it in English", p.name, s.Kind, s.ID, p.locale)
+		}
+		due = append(due, preview{season: s, text: text, lang: lang})
+	}
+	return due, nil
+}
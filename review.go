@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/rosszurowski/small-seasons-bot/ledger"
+	"github.com/rosszurowski/small-seasons-bot/seasons"
+)
+
+var (
+	// reviewTemplate renders the first post of the year in review thread.
+	reviewTemplate = seasons.MustParseTemplate("review", seasons.ReviewTemplate)
+	// reviewEntryTemplate renders the post for each season in the thread.
+	reviewEntryTemplate = seasons.MustParseTemplate("review-entry", seasons.ReviewEntryTemplate)
+)
+
+// reviewEntry is a season in the year in review, along with the post it was
+// announced in.
+type reviewEntry struct {
+	season seasons.Season
+	text   string // the season's line in the thread
+	post   recentPost
+}
+
+// planReview returns the text of the first post of the year in review thread
+// due at now, and a line for each of the year's seasons that was posted, in
+// the order they happened. It returns false if no review is due, or if it's
+// already been posted. Posts are looked up in the ledger, or failing that,
+// among the account's posts from the past year, which are only fetched if
+// they're needed.
+func planReview(cal *seasons.Calendar, p platform, now time.Time, fetch func() ([]recentPost, error)) (string, []reviewEntry, bool, error) {
+	year, all, ok := cal.Review(now)
+	if !ok {
+		return "", nil, false, nil
+	}
+	if p.locale != seasons.English {
+		log.Printf("%s: reviews are only written in English, skipping the %d review", p.name, year)
+		return "", nil, false, nil
+	}
+	root, err := reviewTemplate.Execute(seasons.ReviewData{Year: year, Seasons: all})
+	if err != nil {
+		return "", nil, false, fmt.Errorf("rendering review: %w", err)
+	}
+	if _, ok := p.ledger.Find(p.name, ledger.KindReview, "", year); ok {
+		log.Printf("%s: already posted the %d review", p.name, year)
+		return "", nil, false, nil
+	}
+	var posts []recentPost
+	fetched := false
+	history := func() ([]recentPost, error) {
+		if !fetched {
+			if posts, err = fetch(); err != nil {
+				return nil, err
+			}
+			fetched = true
+		}
+		return posts, nil
+	}
+	if p.needsHistory() {
+		posts, err := history()
+		if err != nil {
+			return "", nil, false, err
+		}
+		for _, post := range posts {
+			if post.text == root {
+				log.Printf("%s: already posted the %d review", p.name, year)
+				return "", nil, false, nil
+			}
+		}
+	}
+
+	var entries []reviewEntry
+	for _, s := range all {
+		post, ok, err := reviewedPost(cal, p, s, history)
+		if err != nil {
+			return "", nil, false, err
+		}
+		if !ok {
+			log.Printf("%s: couldn't find the post for %s %s, leaving it out of the review", p.name, s.Kind, s.ID)
+			continue
+		}
+		text, err := reviewEntryTemplate.Execute(s)
+		if err != nil {
+			return "", nil, false, fmt.Errorf("rendering review of %s: %w", s.ID, err)
+		}
+		entries = append(entries, reviewEntry{season: s, text: text, post: post})
+	}
+	if len(entries) == 0 {
+		log.Printf("%s: no season posts found for the %d review", p.name, year)
+		return "", nil, false, nil
+	}
+	return root, entries, true, nil
+}
+
+// reviewedPost returns the post that announced s, from the ledger or failing
+// that, from the account's posts history returns.
+func reviewedPost(cal *seasons.Calendar, p platform, s seasons.Season, history func() ([]recentPost, error)) (recentPost, bool, error) {
+	if e, ok := p.ledger.Find(p.name, ledger.KindSeason, s.ID, s.Date.Year()); ok {
+		return fromEntry(e), true, nil
+	}
+	posts, err := history()
+	if err != nil {
+		return recentPost{}, false, err
+	}
+	i, err := findSeasonPost(cal, p, s, posts)
+	if err != nil || i < 0 {
+		return recentPost{}, false, err
+	}
+	return posts[i], true, nil
+}
+
+// postReview posts the year in review to acct on New Year's Eve, as a thread
+// with a reply for each of the year's season posts, quoting it where p can
+// quote posts and linking to it where it can't.
+func (p platform) postReview(ctx context.Context, acct account, cal *seasons.Calendar, now time.Time) error {
+	year, _, ok := cal.Review(now)
+	if !ok {
+		return nil
+	}
+	rootText, entries, ok, err := planReview(cal, p, now, func() ([]recentPost, error) {
+		posts, err := acct.since(ctx, now.AddDate(-1, 0, -1))
+		if err != nil {
+			return nil, fmt.Errorf("getting posts: %w", err)
+		}
+		return posts, nil
+	})
+	if err != nil || !ok {
+		return err
+	}
+
+	langs := []string{seasons.English}
+	root, err := p.publish(ctx, acct, draft{what: fmt.Sprintf("%d review", year), text: rootText, langs: langs, kind: ledger.KindReview, year: year}, now)
+	if err != nil {
+		return err
+	}
+	parent := root
+	for _, e := range entries {
+		d := draft{
+			what:   "review of " + describe([]seasons.Season{e.season}),
+			text:   e.text,
+			langs:  langs,
+			root:   root,
+			parent: parent,
+			kind:   ledger.KindReview,
+			group:  []seasons.Season{e.season},
+		}
+		if p.quotes {
+			d.quote = e.post
+		} else {
+			d.text += "\n" + e.post.url
+		}
+		if parent, err = p.publish(ctx, acct, d, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,435 @@
+package seasons
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rosszurowski/small-seasons-bot/astro"
+)
+
+// jst is Japan Standard Time, which the sekki calendar is kept in, and the
+// zone calendars are kept in by default.
+var jst = time.FixedZone("JST", 9*60*60)
+
+// Calendar dates a set of season definitions for any year. Seasons are
+// calculated on demand and cached per year, so a Calendar is safe to keep
+// around and share between goroutines.
+//
+// A calendar with both sekki and kō answers questions like Current and Next
+// with whichever season began most recently; use Only to narrow it to a
+// single kind.
+type Calendar struct {
+	name       string // name of the built-in calendar, or empty for others
+	defs       []Definition
+	kinds      map[Kind]bool // kinds to include, or nil for all of them
+	postTime   PostTime
+	location   *time.Location // zone the calendar is kept in
+	hemisphere Hemisphere
+	sameDay    SameDay
+	place      astro.Place // where sunrise and sunset are seen from
+
+	mu    sync.Mutex
+	years map[int][]Season
+}
+
+// New returns a calendar for the given season definitions, which must pass
+// Validate.
+func New(defs []Definition, opts ...Option) (*Calendar, error) {
+	if err := Validate(defs); err != nil {
+		return nil, fmt.Errorf("invalid seasons: %w", err)
+	}
+	c := &Calendar{
+		defs:       defs,
+		postTime:   PostTime{Mode: PostAtStart},
+		location:   jst,
+		hemisphere: North,
+		sameDay:    SameDayInOrder,
+		place:      astro.Tokyo,
+		years:      make(map[int][]Season),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.postTime.Location == nil {
+		c.postTime.Location = c.location
+	}
+	return c, nil
+}
+
+// Only returns a copy of the calendar restricted to the given kinds of season.
+func (c *Calendar) Only(kinds ...Kind) *Calendar {
+	only := make(map[Kind]bool, len(kinds))
+	for _, k := range kinds {
+		only[k] = true
+	}
+	return &Calendar{
+		defs:       c.defs,
+		kinds:      only,
+		postTime:   c.postTime,
+		location:   c.location,
+		hemisphere: c.hemisphere,
+		sameDay:    c.sameDay,
+		place:      c.place,
+		years:      make(map[int][]Season),
+	}
+}
+
+// Hemisphere returns the hemisphere the calendar is kept for.
+func (c *Calendar) Hemisphere() Hemisphere {
+	return c.hemisphere
+}
+
+// PostTime returns the policy the calendar uses to decide when seasons are
+// posted.
+func (c *Calendar) PostTime() PostTime {
+	return c.postTime
+}
+
+// Definitions returns the season definitions the calendar was built from.
+func (c *Calendar) Definitions() []Definition {
+	return c.defs
+}
+
+// Year returns every season whose term falls in the given year, sorted by
+// start time. Each definition appears exactly once, though terms close to New
+// Year may start just outside of it.
+func (c *Calendar) Year(year int) []Season {
+	return append([]Season(nil), c.year(year)...)
+}
+
+// Current returns the season in effect at t.
+func (c *Calendar) Current(t time.Time) (Season, bool) {
+	seasons := c.around(t, t)
+	for i := len(seasons) - 1; i >= 0; i-- {
+		if !seasons[i].Start.After(t) {
+			return seasons[i], true
+		}
+	}
+	return Season{}, false
+}
+
+// Next returns the first season to start after t.
+func (c *Calendar) Next(t time.Time) (Season, bool) {
+	for _, s := range c.around(t, t) {
+		if s.Start.After(t) {
+			return s, true
+		}
+	}
+	return Season{}, false
+}
+
+// Previous returns the season before the one in effect at t.
+func (c *Calendar) Previous(t time.Time) (Season, bool) {
+	seasons := c.around(t, t)
+	for i := len(seasons) - 1; i > 0; i-- {
+		if !seasons[i].Start.After(t) {
+			return seasons[i-1], true
+		}
+	}
+	return Season{}, false
+}
+
+// YearBefore returns s as it was a year earlier, if the calendar had it then.
+func (c *Calendar) YearBefore(s Season) (Season, bool) {
+	year := s.Start.AddDate(-1, 0, 0)
+	for _, prev := range c.InRange(year.AddDate(0, 0, -30), year.AddDate(0, 0, 30)) {
+		if prev.ID == s.ID {
+			return prev, true
+		}
+	}
+	return Season{}, false
+}
+
+// nextOfKind returns the first season of the same kind as s to start after
+// it.
+func (c *Calendar) nextOfKind(s Season) (Season, bool) {
+	for _, other := range c.around(s.Start, s.Start) {
+		if other.Kind == s.Kind && other.Start.After(s.Start) {
+			return other, true
+		}
+	}
+	return Season{}, false
+}
+
+// InRange returns the seasons starting between from (inclusive) and to
+// (exclusive), in order.
+func (c *Calendar) InRange(from, to time.Time) []Season {
+	var seasons []Season
+	for _, s := range c.around(from, to) {
+		if !s.Start.Before(from) && s.Start.Before(to) {
+			seasons = append(seasons, s)
+		}
+	}
+	return seasons
+}
+
+// DaysRemaining returns the number of days left in the season in effect at t,
+// counting calendar days in the post time's zone. It returns 0 on the last
+// day of a season.
+func (c *Calendar) DaysRemaining(t time.Time) int {
+	next, ok := c.Next(t)
+	if !ok {
+		return 0
+	}
+	loc := c.postTime.Location
+	y1, m1, d1 := t.In(loc).Date()
+	y2, m2, d2 := next.Start.In(loc).Date()
+	today := time.Date(y1, m1, d1, 0, 0, 0, 0, time.UTC)
+	last := time.Date(y2, m2, d2, 0, 0, 0, 0, time.UTC).AddDate(0, 0, -1)
+	return int(last.Sub(today).Hours() / 24)
+}
+
+// Schedule returns the seasons to be posted in the year before, the year of,
+// and the year after now, sorted by post time. A kō that starts together with
+// its sekki is posted on the same day, after it or along with it, as the
+// calendar's same-day policy says.
+func (c *Calendar) Schedule(now time.Time) []Season {
+	schedule := c.around(now, now)
+	sort.SliceStable(schedule, func(i, j int) bool {
+		return schedule[i].Date.Before(schedule[j].Date)
+	})
+	return schedule
+}
+
+// priority is the order seasons posted on the same day go out in.
+var priority = map[Kind]int{
+	KindSekki:    0,
+	KindSekku:    1,
+	KindZassetsu: 2,
+	KindKo:       3,
+}
+
+// Unposted returns the seasons that should go out together in the next post
+// at now, or an error if there's nothing to post. posted reports whether a
+// season has already gone out, from the account's record of its posts. Days
+// are compared in the post time's zone.
+//
+// Seasons posted on the same day are sorted by kind, sekki first, then
+// festivals, zassetsu and kō. Posted in order, each post made on the day
+// moves on to the next of them; combined, they're all returned at once, once
+// the last of them is due. Days that have been posted are passed over, so a
+// season due the day after another isn't held up by it.
+func (c *Calendar) Unposted(now time.Time, posted func(Season) bool) ([]Season, error) {
+	days := c.upcoming(now)
+	pending := false
+	for _, day := range days {
+		var left []Season
+		for _, s := range day {
+			if !posted(s) {
+				left = append(left, s)
+			}
+		}
+		// A day that's been posted, even in part when combining, is done
+		// with.
+		if len(left) == 0 || (c.sameDay == SameDayCombine && len(left) < len(day)) {
+			continue
+		}
+		pending = true
+		if c.sameDay == SameDayCombine {
+			if c.combinedDue(day, now) {
+				return day, nil
+			}
+			continue
+		}
+		if next := left[0]; c.postTime.Due(next.Date, now) {
+			return []Season{next}, nil
+		}
+	}
+	if len(days) > 0 && !pending {
+		return nil, ErrAlreadyPosted
+	}
+	return nil, ErrNoSeason
+}
+
+// NextRun returns the next time after now there's something to post: the
+// next season's post, a preview of one coming due previewDays ahead of its
+// post for any of the given numbers of days, or the start of the next day's
+// posts, when kigo and reviews go out. That's the post time's time of day, or
+// midnight in its zone when posts aren't pinned to one.
+func (c *Calendar) NextRun(now time.Time, previewDays ...int) time.Time {
+	loc := c.postTime.Location
+	y, m, d := now.In(loc).Date()
+	next := time.Date(y, m, d, 0, 0, 0, 0, loc)
+	if c.postTime.Mode == PostAtClock {
+		next = c.postTime.clockOn(now)
+	}
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	for _, s := range c.Schedule(now) {
+		due := []time.Time{s.Date}
+		for _, days := range previewDays {
+			if days > 0 {
+				due = append(due, s.Date.AddDate(0, 0, -days))
+			}
+		}
+		for _, at := range due {
+			if at.After(now) && at.Before(next) {
+				next = at
+			}
+		}
+	}
+	return next
+}
+
+// upcoming returns the seasons posted within a day either side of now,
+// grouped by the day they're posted on and sorted by priority within it.
+func (c *Calendar) upcoming(now time.Time) [][]Season {
+	oneDayAgo := now.Add(time.Hour * -24)
+	oneDayFromNow := now.Add(time.Hour * 24)
+	var upcoming []Season
+	for _, s := range c.Schedule(now) {
+		if s.Date.Before(oneDayAgo) {
+			// We're running this cron job more frequently than every 24 hours,
+			// so ignore dates before then.
+			continue
+		}
+		if s.Date.After(oneDayFromNow) {
+			// Ignore dates in the future. Our seasons are days apart, so this
+			// rough check should never be an issue.
+			continue
+		}
+		upcoming = append(upcoming, s)
+	}
+
+	var days [][]Season
+	for len(upcoming) > 0 {
+		n := 1
+		for n < len(upcoming) && c.postTime.SameDay(upcoming[n].Date, upcoming[0].Date) {
+			n++
+		}
+		day := upcoming[:n]
+		upcoming = upcoming[n:]
+		sort.SliceStable(day, func(i, j int) bool {
+			return priority[day[i].Kind] < priority[day[j].Kind]
+		})
+		days = append(days, day)
+	}
+	return days
+}
+
+// combinedDue reports whether a day's seasons, posted together, are due at
+// now: they wait for the last of them.
+func (c *Calendar) combinedDue(day []Season, now time.Time) bool {
+	last := day[0].Date
+	for _, s := range day {
+		if s.Date.After(last) {
+			last = s.Date
+		}
+	}
+	return c.postTime.Due(last, now)
+}
+
+// around returns the seasons from the year before from to the year after to,
+// sorted by start time.
+func (c *Calendar) around(from, to time.Time) []Season {
+	var seasons []Season
+	for year := from.Year() - 1; year <= to.Year()+1; year++ {
+		seasons = append(seasons, c.year(year)...)
+	}
+	// Terms near New Year can land on either side of it, so sort again once
+	// the years are joined.
+	sort.SliceStable(seasons, func(i, j int) bool {
+		return seasons[i].Start.Before(seasons[j].Start)
+	})
+	return seasons
+}
+
+// year returns the seasons whose terms fall in the given year, sorted by
+// start time.
+func (c *Calendar) year(year int) []Season {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if seasons, ok := c.years[year]; ok {
+		return seasons
+	}
+
+	var seasons []Season
+	add := func(d Definition, kind Kind, parent string) {
+		if c.kinds != nil && !c.kinds[kind] {
+			return
+		}
+		start := c.start(d, year)
+		description, variant := d.DescriptionFor(year)
+		native := d.Native
+		if native == "" {
+			native = d.Japanese
+		}
+		seasons = append(seasons, Season{
+			ID:                  d.ID,
+			Kind:                kind,
+			Calendar:            c.name,
+			Parent:              parent,
+			Start:               start,
+			Date:                c.postTime.At(start),
+			Title:               d.Title,
+			Japanese:            d.Japanese,
+			Reading:             d.Reading,
+			Native:              native,
+			Description:         description,
+			Variant:             variant,
+			Emoji:               d.Emoji,
+			JapaneseDescription: d.JapaneseDescription,
+		})
+	}
+	for _, d := range c.defs {
+		kind := d.Kind
+		if kind == "" {
+			kind = KindSekki
+		}
+		add(d, kind, "")
+		for _, k := range d.Ko {
+			add(k, KindKo, d.ID)
+		}
+	}
+	sort.SliceStable(seasons, func(i, j int) bool {
+		return seasons[i].Start.Before(seasons[j].Start)
+	})
+	c.years[year] = seasons
+	return seasons
+}
+
+// start returns the moment a season starts in the given year. Seasons with a
+// fixed date, or dated from another season, start at midnight in the post
+// time's zone.
+func (c *Calendar) start(d Definition, year int) time.Time {
+	if d.From != "" {
+		from := c.start(c.definition(d.From), year).In(c.postTime.Location)
+		y, m, day := from.Date()
+		return time.Date(y, m, day+d.Days, 0, 0, 0, 0, c.postTime.Location)
+	}
+	south := c.hemisphere == South
+	if d.Longitude != nil {
+		lon := *d.Longitude
+		if south {
+			lon = math.Mod(lon+180, 360)
+		}
+		return astro.SolarTerm(year, lon)
+	}
+	date, _ := time.Parse("2006-"+startDateLayout, "2000-"+d.StartDate)
+	month := date.Month()
+	if south {
+		// Wrap within the year, so the season still falls in it.
+		month = (month+5)%12 + 1
+	}
+	return time.Date(year, month, date.Day(), 0, 0, 0, 0, c.postTime.Location)
+}
+
+// definition returns the definition with the given ID, which Validate has
+// already checked exists.
+func (c *Calendar) definition(id string) Definition {
+	for _, d := range c.defs {
+		if d.ID == id {
+			return d
+		}
+		for _, k := range d.Ko {
+			if k.ID == id {
+				return k
+			}
+		}
+	}
+	return Definition{}
+}
@@ -0,0 +1,448 @@
+package seasons
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestCalendar(t *testing.T) {
+	cal, err := Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sekki := cal.Only(KindSekki)
+
+	t.Run("finds the current season", func(t *testing.T) {
+		now := time.Date(2026, time.February, 10, 12, 0, 0, 0, jst)
+		s, ok := sekki.Current(now)
+		if !ok || s.ID != "risshun" {
+			t.Errorf("expected risshun, got %v", s.ID)
+		}
+		s, ok = cal.Current(now)
+		if !ok || s.ID != "koo-kenkansu" {
+			t.Errorf("expected koo-kenkansu, got %v", s.ID)
+		}
+		if s.Parent != "risshun" {
+			t.Errorf("expected parent risshun, got %v", s.Parent)
+		}
+	})
+
+	t.Run("finds a season a year earlier", func(t *testing.T) {
+		// Shokan starts on January 5th 2026, and on January 5th 2025 too.
+		s, ok := sekki.Current(time.Date(2026, time.January, 10, 12, 0, 0, 0, jst))
+		if !ok || s.ID != "shokan" {
+			t.Fatalf("expected shokan, got %v", s.ID)
+		}
+		prev, ok := sekki.YearBefore(s)
+		if !ok || prev.ID != "shokan" || prev.Start.Year() != 2025 {
+			t.Errorf("expected shokan 2025, got %v %v", prev.ID, prev.Start)
+		}
+	})
+
+	t.Run("marks each season with its year", func(t *testing.T) {
+		s, _ := sekki.Current(time.Date(2026, time.February, 10, 12, 0, 0, 0, jst))
+		if got := s.Marker(); got != "sekki:risshun:2026" {
+			t.Errorf("expected sekki:risshun:2026, got %v", got)
+		}
+		prev, _ := sekki.YearBefore(s)
+		if prev.Marker() == s.Marker() {
+			t.Errorf("expected a different marker a year earlier, got %v", prev.Marker())
+		}
+	})
+
+	t.Run("marks other calendars' seasons with the calendar", func(t *testing.T) {
+		for _, tt := range []struct {
+			provider Provider
+			want     string
+		}{
+			{Jieqi, "jieqi:lichun:2027"},
+			{Wheel, "wheel:imbolc:2027"},
+		} {
+			cal, err := FromProvider(tt.provider)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s, ok := cal.Current(time.Date(2027, time.February, 10, 12, 0, 0, 0, time.UTC))
+			if !ok {
+				t.Fatal("expected a season")
+			}
+			if got := s.Marker(); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		}
+	})
+
+	t.Run("finds the next and previous seasons across New Year", func(t *testing.T) {
+		now := time.Date(2026, time.December, 31, 23, 30, 0, 0, jst)
+		next, ok := sekki.Next(now)
+		if !ok || next.ID != "shokan" || next.Start.Year() != 2027 {
+			t.Errorf("expected shokan 2027, got %v %v", next.ID, next.Start)
+		}
+		prev, ok := sekki.Previous(now)
+		if !ok || prev.ID != "taisetsu" {
+			t.Errorf("expected taisetsu, got %v", prev.ID)
+		}
+	})
+
+	t.Run("lists seasons in a range", func(t *testing.T) {
+		from := time.Date(2026, time.January, 1, 0, 0, 0, 0, jst)
+		to := time.Date(2027, time.January, 1, 0, 0, 0, 0, jst)
+		if got := len(sekki.InRange(from, to)); got != 24 {
+			t.Errorf("expected 24 sekki in 2026, got %v", got)
+		}
+		// The kō at 280° lands on New Year's Eve in 2025 and New Year's Day
+		// in 2027, so count a full cycle from February instead.
+		from = time.Date(2026, time.February, 1, 0, 0, 0, 0, jst)
+		to = time.Date(2027, time.February, 1, 0, 0, 0, 0, jst)
+		if got := len(cal.Only(KindKo).InRange(from, to)); got != 72 {
+			t.Errorf("expected 72 kō from February 2026, got %v", got)
+		}
+	})
+
+	t.Run("counts the days remaining", func(t *testing.T) {
+		// Usui 2026 starts at 00:51 JST on February 19th.
+		now := time.Date(2026, time.February, 16, 9, 0, 0, 0, jst)
+		if got := sekki.DaysRemaining(now); got != 2 {
+			t.Errorf("expected 2 days remaining, got %v", got)
+		}
+	})
+}
+
+func TestZassetsu(t *testing.T) {
+	cal, err := Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	zassetsu := cal.Only(KindZassetsu)
+
+	t.Run("dates days from their sekki", func(t *testing.T) {
+		// Risshun 2026 is on February 4th and shunbun on March 20th.
+		want := map[string]string{
+			"setsubun":        "2026-02-03",
+			"haru-no-higan":   "2026-03-17",
+			"hachiju-hachiya": "2026-05-02",
+			"nihyaku-toka":    "2026-09-01",
+		}
+		for _, s := range zassetsu.Year(2026) {
+			if date, ok := want[s.ID]; ok {
+				if got := s.Start.Format(time.DateOnly); got != date {
+					t.Errorf("expected %s on %s, got %s", s.ID, date, got)
+				}
+				delete(want, s.ID)
+			}
+		}
+		for id := range want {
+			t.Errorf("expected %s in 2026", id)
+		}
+	})
+
+	t.Run("posts zassetsu alongside sekki", func(t *testing.T) {
+		now := time.Date(2026, time.February, 3, 12, 0, 0, 0, jst)
+		seasons, err := cal.Unposted(now, postedIDs())
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(seasons) != 1 || seasons[0].ID != "setsubun" || seasons[0].Kind != KindZassetsu {
+			t.Errorf("expected setsubun, got %v", seasons)
+		}
+	})
+}
+
+func TestSameDay(t *testing.T) {
+	postTime, err := ParsePostTime("16:02", "Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Rikka, its first kō and Tango no sekku all fall on May 5th in 2026.
+	now := time.Date(2026, time.May, 5, 16, 30, 0, 0, postTime.Location)
+
+	t.Run("posts seasons on the same day in order", func(t *testing.T) {
+		cal, err := Default(WithPostTime(postTime))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var posted []string
+		for _, want := range []string{"rikka", "tango-no-sekku", "kawazu-hajimete-naku"} {
+			seasons, err := cal.Unposted(now, postedIDs(posted...))
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if len(seasons) != 1 || seasons[0].ID != want {
+				t.Errorf("expected %v, got %v", want, seasons)
+			}
+			posted = append(posted, want)
+		}
+		if _, err := cal.Unposted(now, postedIDs(posted...)); !errors.Is(err, ErrAlreadyPosted) {
+			t.Errorf("expected ErrAlreadyPosted, got %v", err)
+		}
+	})
+
+	t.Run("combines seasons on the same day", func(t *testing.T) {
+		cal, err := Default(WithPostTime(postTime), WithSameDay(SameDayCombine))
+		if err != nil {
+			t.Fatal(err)
+		}
+		seasons, err := cal.Unposted(now, postedIDs())
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(seasons) != 3 || seasons[0].ID != "rikka" || seasons[1].ID != "tango-no-sekku" || seasons[2].ID != "kawazu-hajimete-naku" {
+			t.Errorf("expected rikka, tango-no-sekku and kawazu-hajimete-naku, got %v", seasons)
+		}
+		// A day posted in part when combining counts as done.
+		if _, err := cal.Unposted(now, postedIDs("rikka")); !errors.Is(err, ErrAlreadyPosted) {
+			t.Errorf("expected ErrAlreadyPosted, got %v", err)
+		}
+	})
+
+	t.Run("moves on to seasons on the next day", func(t *testing.T) {
+		cal, err := Default(WithPostTime(postTime))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Setsubun falls on February 3rd 2027, and risshun the day after.
+		now := time.Date(2027, time.February, 4, 16, 2, 0, 0, postTime.Location)
+		seasons, err := cal.Unposted(now, postedIDs("setsubun"))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(seasons) != 1 || seasons[0].ID != "risshun" {
+			t.Errorf("expected risshun, got %v", seasons)
+		}
+	})
+}
+
+func TestHemisphere(t *testing.T) {
+	north, err := Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	south, err := Default(WithHemisphere(South))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("starts seasons at the opposite term", func(t *testing.T) {
+		now := time.Date(2026, time.August, 10, 12, 0, 0, 0, jst)
+		s, ok := south.Only(KindSekki).Current(now)
+		if !ok || s.ID != "risshun" {
+			t.Errorf("expected risshun, got %v", s.ID)
+		}
+		risshu, _ := north.Only(KindSekki).Current(now)
+		if !s.Start.Equal(risshu.Start) {
+			t.Errorf("expected risshun to start at %v, got %v", risshu.Start, s.Start)
+		}
+	})
+
+	t.Run("shifts fixed dates by six months", func(t *testing.T) {
+		cal, err := New([]Definition{
+			{ID: "new-year", Title: "New Year", StartDate: "01-01"},
+			{ID: "tanabata", Title: "Tanabata", StartDate: "07-07"},
+		}, WithHemisphere(South))
+		if err != nil {
+			t.Fatal(err)
+		}
+		year := cal.Year(2026)
+		if year[0].ID != "tanabata" || year[0].Start.Month() != time.January {
+			t.Errorf("expected tanabata in January, got %v %v", year[0].ID, year[0].Start)
+		}
+		if year[1].ID != "new-year" || year[1].Start.Month() != time.July {
+			t.Errorf("expected new-year in July, got %v %v", year[1].ID, year[1].Start)
+		}
+	})
+}
+
+func TestUnposted(t *testing.T) {
+	postTime, err := ParsePostTime("16:02", "Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cal, err := Default(WithPostTime(postTime))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("finds shokan in early January", func(t *testing.T) {
+		// Shokan 2027 starts on January 5th in Japan.
+		now := time.Date(2027, time.January, 5, 16, 30, 0, 0, postTime.Location)
+		seasons, err := cal.Unposted(now, postedIDs())
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(seasons) != 1 || seasons[0].ID != "shokan" {
+			t.Errorf("expected shokan, got %v", seasons)
+		}
+	})
+
+	t.Run("posts kō on their own dates", func(t *testing.T) {
+		// Kōō kenkansu 2026 starts on February 9th in Japan.
+		now := time.Date(2026, time.February, 9, 16, 2, 0, 0, postTime.Location)
+		seasons, err := cal.Unposted(now, postedIDs())
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(seasons) != 1 || seasons[0].ID != "koo-kenkansu" || seasons[0].Kind != KindKo {
+			t.Errorf("expected koo-kenkansu, got %v", seasons)
+		}
+	})
+
+	t.Run("posts a sekki's first kō after it", func(t *testing.T) {
+		// Seri sunawachi sakau starts together with shokan.
+		now := time.Date(2027, time.January, 5, 17, 30, 0, 0, postTime.Location)
+		seasons, err := cal.Unposted(now, postedIDs("shokan"))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(seasons) != 1 || seasons[0].ID != "seri-sunawachi-sakau" || seasons[0].Kind != KindKo {
+			t.Errorf("expected seri-sunawachi-sakau, got %v", seasons)
+		}
+	})
+
+	t.Run("doesn't post twice on the same day", func(t *testing.T) {
+		now := time.Date(2027, time.January, 5, 17, 30, 0, 0, postTime.Location)
+		_, err := cal.Unposted(now, postedIDs("shokan", "seri-sunawachi-sakau"))
+		if !errors.Is(err, ErrAlreadyPosted) {
+			t.Errorf("expected ErrAlreadyPosted, got %v", err)
+		}
+	})
+}
+
+// postedIDs returns a posted func for Unposted that reports the seasons with
+// the given IDs as posted.
+func postedIDs(ids ...string) func(Season) bool {
+	return func(s Season) bool {
+		return slices.Contains(ids, s.ID)
+	}
+}
+
+func TestNextRun(t *testing.T) {
+	t.Run("runs daily at the post time", func(t *testing.T) {
+		postTime, err := ParsePostTime("16:02", "Asia/Tokyo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		cal, err := Default(WithPostTime(postTime))
+		if err != nil {
+			t.Fatal(err)
+		}
+		loc := postTime.Location
+		for now, want := range map[time.Time]time.Time{
+			time.Date(2026, time.February, 9, 9, 0, 0, 0, loc):  time.Date(2026, time.February, 9, 16, 2, 0, 0, loc),
+			time.Date(2026, time.February, 9, 16, 2, 0, 0, loc): time.Date(2026, time.February, 10, 16, 2, 0, 0, loc),
+		} {
+			if got := cal.NextRun(now); !got.Equal(want) {
+				t.Errorf("expected %v at %v, got %v", want, now, got)
+			}
+		}
+	})
+
+	t.Run("keeps the time of day when the clocks change", func(t *testing.T) {
+		postTime, err := ParsePostTime("16:02", "Europe/Berlin")
+		if err != nil {
+			t.Fatal(err)
+		}
+		cal, err := New([]Definition{{ID: "new-year", Title: "New Year", StartDate: "01-01"}}, WithPostTime(postTime))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Summer time starts in Berlin on March 28th 2027.
+		loc := postTime.Location
+		for now, want := range map[time.Time]time.Time{
+			time.Date(2027, time.March, 28, 9, 0, 0, 0, loc):  time.Date(2027, time.March, 28, 16, 2, 0, 0, loc),
+			time.Date(2027, time.March, 27, 16, 2, 0, 0, loc): time.Date(2027, time.March, 28, 16, 2, 0, 0, loc),
+		} {
+			if got := cal.NextRun(now); !got.Equal(want) {
+				t.Errorf("expected %v at %v, got %v", want, now, got)
+			}
+		}
+	})
+
+	t.Run("wakes for seasons posted as they start", func(t *testing.T) {
+		postTime, err := ParsePostTime("start", "Asia/Tokyo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		cal, err := Default(WithPostTime(postTime))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Usui 2026 starts at 00:51 JST on February 19th.
+		loc := postTime.Location
+		if got, want := cal.NextRun(time.Date(2026, time.February, 18, 23, 0, 0, 0, loc)), time.Date(2026, time.February, 19, 0, 0, 0, 0, loc); !got.Equal(want) {
+			t.Errorf("expected midnight, got %v", got)
+		}
+		got := cal.NextRun(time.Date(2026, time.February, 19, 0, 10, 0, 0, loc))
+		if got.Hour() != 0 || got.Minute() != 51 {
+			t.Errorf("expected usui at 00:51, got %v", got)
+		}
+	})
+
+	t.Run("wakes for previews posted as they come due", func(t *testing.T) {
+		postTime, err := ParsePostTime("start", "Asia/Tokyo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		cal, err := Default(WithPostTime(postTime))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Usui 2026 starts at 00:51 JST on February 19th, so its preview three
+		// days ahead is due at 00:51 on the 16th.
+		loc := postTime.Location
+		now := time.Date(2026, time.February, 16, 0, 10, 0, 0, loc)
+		if got, want := cal.NextRun(now), time.Date(2026, time.February, 17, 0, 0, 0, 0, loc); !got.Equal(want) {
+			t.Errorf("expected midnight without previews, got %v", got)
+		}
+		if got := cal.NextRun(now, 0, 3); got.Day() != 16 || got.Hour() != 0 || got.Minute() != 51 {
+			t.Errorf("expected usui's preview at 00:51, got %v", got)
+		}
+	})
+}
+
+func TestVariants(t *testing.T) {
+	d := Definition{
+		ID:          "risshun",
+		Description: "one",
+		Variants:    []string{"two", "three"},
+	}
+
+	t.Run("rotates through every variant", func(t *testing.T) {
+		seen := make(map[string]bool)
+		for year := 2026; year < 2029; year++ {
+			description, _ := d.DescriptionFor(year)
+			seen[description] = true
+		}
+		if len(seen) != 3 {
+			t.Errorf("expected 3 variants over 3 years, got %v", len(seen))
+		}
+	})
+
+	t.Run("picks the same variant for the same year", func(t *testing.T) {
+		a, i := d.DescriptionFor(2026)
+		b, j := d.DescriptionFor(2026)
+		if a != b || i != j {
+			t.Errorf("expected %q, got %q", a, b)
+		}
+	})
+
+	t.Run("uses the description without variants", func(t *testing.T) {
+		d := Definition{ID: "risshun", Description: "one"}
+		if got, _ := d.DescriptionFor(2026); got != "one" {
+			t.Errorf("expected one, got %q", got)
+		}
+	})
+
+	t.Run("schedules a different variant each year", func(t *testing.T) {
+		cal, err := Default()
+		if err != nil {
+			t.Fatal(err)
+		}
+		a, _ := cal.Only(KindSekki).Current(time.Date(2026, time.February, 10, 0, 0, 0, 0, jst))
+		b, _ := cal.Only(KindSekki).Current(time.Date(2027, time.February, 10, 0, 0, 0, 0, jst))
+		if a.ID != b.ID || a.Description == b.Description {
+			t.Errorf("expected different descriptions, got %q and %q", a.Description, b.Description)
+		}
+	})
+}
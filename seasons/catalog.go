@@ -0,0 +1,144 @@
+package seasons
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+//go:embed locales/*.json
+var locales embed.FS
+
+// English is the locale the season datasets are written in.
+const English = "en"
+
+// Japanese is the locale of the Japanese text carried by the sekki datasets
+// themselves.
+const Japanese = "ja"
+
+// Chinese is the locale the jieqi are translated into, in simplified
+// characters.
+const Chinese = "zh"
+
+// Translation is a season's text in another language.
+type Translation struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// Catalog holds translations of season text, keyed by locale and season ID.
+// English is the text of the datasets, and Japanese the Japanese text they
+// carry, so neither needs a catalog file of its own. A locale needn't cover
+// every calendar: Chinese and Korean only translate the jieqi and jeolgi.
+type Catalog struct {
+	locales map[string]map[string]Translation
+}
+
+// DefaultCatalog returns the built-in catalog.
+func DefaultCatalog() (*Catalog, error) {
+	return ReadCatalog(locales, "locales")
+}
+
+// ReadCatalog reads a catalog from the JSON files in dir, each named after
+// its locale, like "de.json", and holding an object of translations keyed by
+// season ID.
+func ReadCatalog(fsys fs.FS, dir string) (*Catalog, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading catalog: %w", err)
+	}
+	c := &Catalog{locales: make(map[string]map[string]Translation)}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || path.Ext(name) != ".json" {
+			continue
+		}
+		b, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("reading catalog: %w", err)
+		}
+		var translations map[string]Translation
+		if err := json.Unmarshal(b, &translations); err != nil {
+			return nil, fmt.Errorf("error loading %s: %w", name, err)
+		}
+		c.locales[strings.TrimSuffix(name, ".json")] = translations
+	}
+	return c, nil
+}
+
+// Locales returns every locale seasons can be posted in, sorted.
+func (c *Catalog) Locales() []string {
+	locales := []string{English, Japanese}
+	for locale := range c.locales {
+		if locale != English && locale != Japanese {
+			locales = append(locales, locale)
+		}
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// HasLocale reports whether seasons can be posted in locale.
+func (c *Catalog) HasLocale(locale string) bool {
+	_, ok := c.locales[locale]
+	return ok || locale == English || locale == Japanese
+}
+
+// Translate returns s with its title and description in locale. It reports
+// false, and returns s as it is, if the catalog has no translation for it.
+func (c *Catalog) Translate(s Season, locale string) (Season, bool) {
+	switch locale {
+	case English:
+		return s, true
+	case Japanese:
+		if s.Japanese == "" || s.JapaneseDescription == "" {
+			return s, false
+		}
+		s.Title, s.Description = s.Japanese, s.JapaneseDescription
+		return s, true
+	}
+	t, ok := c.locales[locale][s.ID]
+	if !ok || t.Title == "" || t.Description == "" {
+		return s, false
+	}
+	s.Title, s.Description = t.Title, t.Description
+	return s, true
+}
+
+// Missing returns the IDs of the seasons in defs with no translation in
+// locale, in the order they're defined.
+func (c *Catalog) Missing(defs []Definition, locale string) []string {
+	var missing []string
+	check := func(d Definition) {
+		s := Season{ID: d.ID, Japanese: d.Japanese, JapaneseDescription: d.JapaneseDescription}
+		if _, ok := c.Translate(s, locale); !ok {
+			missing = append(missing, d.ID)
+		}
+	}
+	for _, d := range defs {
+		check(d)
+		for _, k := range d.Ko {
+			check(k)
+		}
+	}
+	return missing
+}
+
+// TranslateData returns the template data for a post with the season, and the
+// season after it, in locale. It reports false, and returns data as it is, if
+// the catalog has no translation for the season being posted.
+func (c *Catalog) TranslateData(data TemplateData, locale string) (TemplateData, bool) {
+	s, ok := c.Translate(data.Season, locale)
+	if !ok {
+		return data, false
+	}
+	data.Season = s
+	// The next season is only mentioned in passing, so it can stay in English
+	// if it hasn't been translated yet.
+	data.Next, _ = c.Translate(data.Next, locale)
+	return data, true
+}
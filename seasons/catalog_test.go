@@ -0,0 +1,84 @@
+package seasons
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCatalog(t *testing.T) {
+	c, err := DefaultCatalog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := Season{ID: "risshun", Title: "Start of spring", Japanese: "立春", JapaneseDescription: "暦の上で春が始まる日。"}
+
+	t.Run("lists every locale", func(t *testing.T) {
+		expected := []string{"de", "en", "fr", "ja", "ko", "zh"}
+		if got := c.Locales(); !slices.Equal(got, expected) {
+			t.Errorf("expected %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("translates from the catalog", func(t *testing.T) {
+		got, ok := c.Translate(s, "de")
+		if !ok || got.Title != "Frühlingsanfang" {
+			t.Errorf("expected Frühlingsanfang, got %q %v", got.Title, ok)
+		}
+	})
+
+	t.Run("translates into Japanese from the season itself", func(t *testing.T) {
+		got, ok := c.Translate(s, Japanese)
+		if !ok || got.Title != "立春" || got.Description != s.JapaneseDescription {
+			t.Errorf("expected 立春, got %q %v", got.Title, ok)
+		}
+	})
+
+	t.Run("translates every built-in season", func(t *testing.T) {
+		calendars := map[string]Provider{
+			English:  Sekki,
+			Japanese: Sekki,
+			"de":     Sekki,
+			"fr":     Sekki,
+			"ko":     Jeolgi,
+			Chinese:  Jieqi,
+		}
+		for _, locale := range c.Locales() {
+			p, ok := calendars[locale]
+			if !ok {
+				t.Errorf("expected a calendar for %s", locale)
+				continue
+			}
+			defs, err := p.Definitions()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if missing := c.Missing(defs, locale); len(missing) > 0 {
+				t.Errorf("expected nothing missing from %s in %s, got %v", p.Name(), locale, missing)
+			}
+		}
+	})
+
+	t.Run("translates other calendars into their own language", func(t *testing.T) {
+		got, ok := c.Translate(Season{ID: "lichun", Title: "Start of spring"}, Chinese)
+		if !ok || got.Title != "立春" {
+			t.Errorf("expected 立春, got %q %v", got.Title, ok)
+		}
+		got, ok = c.Translate(Season{ID: "ipchun", Title: "Start of spring"}, "ko")
+		if !ok || got.Title != "입춘" {
+			t.Errorf("expected 입춘, got %q %v", got.Title, ok)
+		}
+	})
+
+	t.Run("reports missing translations", func(t *testing.T) {
+		if _, ok := c.Translate(Season{ID: "lichun"}, "de"); ok {
+			t.Error("expected no German translation for a jieqi term")
+		}
+		defs, err := Jieqi.Definitions()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if missing := c.Missing(defs, "fr"); !slices.Contains(missing, "lichun") {
+			t.Errorf("expected lichun to be missing, got %v", missing)
+		}
+	})
+}
@@ -0,0 +1,82 @@
+package seasons
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// CatchUp is the policy for seasons missed while the bot wasn't running, whose
+// posts have fallen out of the day Unposted looks back over.
+type CatchUp string
+
+const (
+	// CatchUpSkip leaves missed seasons unposted.
+	CatchUpSkip CatchUp = "skip"
+	// CatchUpLate posts missed seasons late, noting when they began.
+	CatchUpLate CatchUp = "late"
+	// CatchUpCurrent posts a missed season late only if the next season of
+	// its kind hasn't started yet.
+	CatchUpCurrent CatchUp = "current"
+)
+
+// ParseCatchUp parses a catch-up policy, either "skip", "late" or "current".
+func ParseCatchUp(s string) (CatchUp, error) {
+	switch p := CatchUp(s); p {
+	case CatchUpSkip, CatchUpLate, CatchUpCurrent:
+		return p, nil
+	}
+	return "", fmt.Errorf("invalid catch-up policy %q: must be skip, late or current", s)
+}
+
+// LateTemplate is the template for the line leading a season posted late,
+// like "Since Tuesday:", or "Since February 4:" once it's a week or more late.
+const LateTemplate = "Since {{if lt .Days 7}}{{weekday .Date}}{{else}}{{date .Date}}{{end}}:"
+
+// LateData is the data available to the late template.
+type LateData struct {
+	Season
+	Days int // days since the season was due
+}
+
+// Missed returns the seasons missed while the bot wasn't running, oldest
+// first: those after the last season posted reports has gone out that were
+// due more than a day before now, too long ago for Unposted to pick up. Which
+// are returned depends on policy. If no season in the schedule has gone out,
+// none are missed, so a new account doesn't backfill the calendar.
+func (c *Calendar) Missed(now time.Time, policy CatchUp, posted func(Season) bool) []Season {
+	if policy != CatchUpLate && policy != CatchUpCurrent {
+		return nil
+	}
+	oneDayAgo := now.Add(time.Hour * -24)
+	schedule := c.Schedule(now)
+	var missed []Season
+	for i := len(schedule) - 1; i >= 0; i-- {
+		s := schedule[i]
+		if !s.Date.Before(oneDayAgo) {
+			continue
+		}
+		if posted(s) {
+			slices.Reverse(missed)
+			return missed
+		}
+		if policy == CatchUpCurrent {
+			if next, ok := c.nextOfKind(s); ok && !next.Start.After(now) {
+				continue
+			}
+		}
+		missed = append(missed, s)
+	}
+	return nil
+}
+
+// Late returns the data for the line leading s when it's posted late at now.
+func (c *Calendar) Late(s Season, now time.Time) LateData {
+	loc := c.postTime.Location
+	y1, m1, d1 := s.Date.In(loc).Date()
+	y2, m2, d2 := now.In(loc).Date()
+	due := time.Date(y1, m1, d1, 0, 0, 0, 0, time.UTC)
+	today := time.Date(y2, m2, d2, 0, 0, 0, 0, time.UTC)
+	s.Date = s.Date.In(loc)
+	return LateData{Season: s, Days: int(today.Sub(due).Hours() / 24)}
+}
@@ -0,0 +1,95 @@
+package seasons
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMissed(t *testing.T) {
+	postTime, err := ParsePostTime("16:02", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cal, err := Default(WithPostTime(postTime))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sekki := cal.Only(KindSekki)
+	// The account last posted risshun, on February 4th 2026.
+	risshun := func(s Season) bool { return s.ID == "risshun" && s.Date.Year() == 2026 }
+
+	ids := func(seasons []Season) []string {
+		ids := make([]string, len(seasons))
+		for i, s := range seasons {
+			ids[i] = s.ID
+		}
+		return ids
+	}
+
+	t.Run("skips missed seasons by default", func(t *testing.T) {
+		now := time.Date(2026, time.February, 21, 17, 0, 0, 0, jst)
+		if missed := sekki.Missed(now, CatchUpSkip, risshun); len(missed) > 0 {
+			t.Errorf("expected nothing, got %v", ids(missed))
+		}
+	})
+
+	t.Run("finds seasons missed more than a day ago", func(t *testing.T) {
+		now := time.Date(2026, time.February, 21, 17, 0, 0, 0, jst)
+		missed := sekki.Missed(now, CatchUpLate, risshun)
+		if len(missed) != 1 || missed[0].ID != "usui" {
+			t.Errorf("expected usui, got %v", ids(missed))
+		}
+		// Yesterday's season is still Unposted's to post.
+		now = time.Date(2026, time.February, 20, 9, 0, 0, 0, jst)
+		if missed := sekki.Missed(now, CatchUpLate, risshun); len(missed) > 0 {
+			t.Errorf("expected nothing, got %v", ids(missed))
+		}
+	})
+
+	t.Run("leaves out seasons already posted", func(t *testing.T) {
+		now := time.Date(2026, time.February, 21, 17, 0, 0, 0, jst)
+		posted := func(s Season) bool { return risshun(s) || s.ID == "usui" }
+		if missed := sekki.Missed(now, CatchUpLate, posted); len(missed) > 0 {
+			t.Errorf("expected nothing, got %v", ids(missed))
+		}
+	})
+
+	t.Run("only catches up on current seasons", func(t *testing.T) {
+		now := time.Date(2026, time.March, 8, 17, 0, 0, 0, jst)
+		late := sekki.Missed(now, CatchUpLate, risshun)
+		if len(late) != 2 || late[0].ID != "usui" || late[1].ID != "keichitsu" {
+			t.Errorf("expected usui and keichitsu, got %v", ids(late))
+		}
+		current := sekki.Missed(now, CatchUpCurrent, risshun)
+		if len(current) != 1 || current[0].ID != "keichitsu" {
+			t.Errorf("expected keichitsu, got %v", ids(current))
+		}
+	})
+
+	t.Run("doesn't backfill an account that's never posted", func(t *testing.T) {
+		now := time.Date(2026, time.February, 21, 17, 0, 0, 0, jst)
+		if missed := sekki.Missed(now, CatchUpLate, func(Season) bool { return false }); len(missed) > 0 {
+			t.Errorf("expected nothing, got %v", ids(missed))
+		}
+	})
+
+	t.Run("says when a late season began", func(t *testing.T) {
+		usui := sekki.Missed(time.Date(2026, time.February, 21, 17, 0, 0, 0, jst), CatchUpLate, risshun)[0]
+		tmpl := MustParseTemplate("late", LateTemplate)
+		for _, tt := range []struct {
+			now  time.Time
+			want string
+		}{
+			{time.Date(2026, time.February, 21, 17, 0, 0, 0, jst), "Since Thursday:"},
+			{time.Date(2026, time.March, 2, 17, 0, 0, 0, jst), "Since February 19:"},
+		} {
+			got, err := tmpl.Execute(sekki.Late(usui, tt.now))
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		}
+	})
+}
@@ -0,0 +1,141 @@
+package seasons
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"strings"
+	"time"
+)
+
+//go:embed kigo.json
+var kigoJSON string
+
+// ErrNoKigo is returned when every kigo of the current season has already been
+// posted.
+var ErrNoKigo = errors.New("no kigo left to post this season")
+
+// KigoTemplate is the template for word of the day posts, which renders posts
+// like "春寒（はるさむ）: The chill that lingers after spring has begun".
+const KigoTemplate = "{{.Kigo}}: {{.Kigo.Gloss}}\n\nA seasonal word for {{.Season.Title}} {{.Season.Emoji}}"
+
+// KigoTag is the hashtag word of the day posts are marked with, so they can be
+// told apart from season posts.
+const KigoTag = "kigo"
+
+// Kigo is a seasonal word, of the kind haiku use to place a poem in its season.
+type Kigo struct {
+	Word    string `json:"word"`
+	Reading string `json:"reading"`
+	Gloss   string `json:"gloss"` // short explanation in English
+}
+
+// String returns the word with its reading, like "春寒（はるさむ）". It's how
+// the word appears in posts, and how posted words are recognised.
+func (k Kigo) String() string {
+	return k.Word + "（" + k.Reading + "）"
+}
+
+// KigoData is the data available to the word of the day template.
+type KigoData struct {
+	Kigo   Kigo
+	Season Season // the season the word was picked from
+}
+
+// Saijiki is a collection of kigo keyed by the ID of the sekki or kō they
+// belong to, named after the seasonal word almanacs haiku poets keep.
+type Saijiki struct {
+	words map[string][]Kigo
+}
+
+// DefaultSaijiki returns the built-in kigo, for the sekki calendar.
+func DefaultSaijiki() (*Saijiki, error) {
+	return ReadSaijiki(strings.NewReader(kigoJSON))
+}
+
+// ReadSaijiki reads kigo from a JSON object of word lists keyed by season ID.
+func ReadSaijiki(r io.Reader) (*Saijiki, error) {
+	var words map[string][]Kigo
+	if err := json.NewDecoder(r).Decode(&words); err != nil {
+		return nil, fmt.Errorf("error loading kigo: %w", err)
+	}
+	return &Saijiki{words: words}, nil
+}
+
+// Words returns the kigo for a season: its own, followed by those of the sekki
+// it belongs to.
+func (k *Saijiki) Words(s Season) []Kigo {
+	words := append([]Kigo(nil), k.words[s.ID]...)
+	if s.Parent != "" {
+		words = append(words, k.words[s.Parent]...)
+	}
+	return words
+}
+
+// Kigo returns the kigo to post at now, along with the season it comes from:
+// the kō in effect, or the top-level season for calendars without kō. posted
+// are the account's recent word of the day posts, keyed by the time they were
+// posted. A word is never repeated within a season, and the choice depends
+// only on the season and what's already been posted, so a rerun picks the
+// same word. The words a kō shares with its sekki aren't repeated within the
+// sekki either.
+//
+// It returns ErrAlreadyPosted if a word has been posted today, ErrNoSeason if
+// it's not yet time to post one, and ErrNoKigo once the season's words run
+// out.
+func (c *Calendar) Kigo(k *Saijiki, now time.Time, posted map[time.Time]string) (Kigo, Season, error) {
+	for t := range posted {
+		if c.postTime.SameDay(t, now) {
+			return Kigo{}, Season{}, ErrAlreadyPosted
+		}
+	}
+	// Words go out at the post time of day, or whenever the bot runs if posts
+	// are timed from the start of each season.
+	if c.postTime.Mode == PostAtClock && !c.postTime.Due(c.postTime.At(now), now) {
+		return Kigo{}, Season{}, ErrNoSeason
+	}
+	s, ok := c.Only(KindKo).Current(now)
+	if !ok {
+		s, ok = c.Only(KindSekki).Current(now)
+	}
+	if !ok {
+		return Kigo{}, Season{}, ErrNoSeason
+	}
+	words := k.Words(s)
+	if len(words) == 0 {
+		return Kigo{}, s, ErrNoKigo
+	}
+	// The sekki's words are shared by each of its kō, so they're checked
+	// against everything posted since the sekki started.
+	own, sekkiStart := len(k.words[s.ID]), s.Start
+	if sekki, ok := c.Only(KindSekki).Current(now); ok && sekki.ID == s.Parent {
+		sekkiStart = sekki.Start
+	}
+
+	// Start from a different word each year, so the same days don't always get
+	// the same words.
+	h := fnv.New32a()
+	h.Write([]byte(s.ID))
+	offset := (int(h.Sum32()%uint32(len(words))) + s.Start.Year()) % len(words)
+	for i := range words {
+		j := (offset + i) % len(words)
+		w, since := words[j], s.Start
+		if j >= own {
+			since = sekkiStart
+		}
+		used := false
+		for t, text := range posted {
+			if !t.Before(since) && strings.Contains(text, w.String()) {
+				used = true
+				break
+			}
+		}
+		if !used {
+			return w, s, nil
+		}
+	}
+	return Kigo{}, s, ErrNoKigo
+}
@@ -0,0 +1,111 @@
+package seasons
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestKigo(t *testing.T) {
+	cal, err := Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	saijiki, err := DefaultSaijiki()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, time.February, 10, 12, 0, 0, 0, jst)
+
+	t.Run("has words for every sekki and kō", func(t *testing.T) {
+		for _, s := range cal.Year(2026) {
+			if s.Kind != KindSekki && s.Kind != KindKo {
+				continue
+			}
+			if len(saijiki.Words(s)) == 0 {
+				t.Errorf("expected kigo for %s", s.ID)
+			}
+		}
+	})
+
+	t.Run("picks a word from the current kō", func(t *testing.T) {
+		k, s, err := cal.Kigo(saijiki, now, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.ID != "koo-kenkansu" {
+			t.Errorf("expected koo-kenkansu, got %s", s.ID)
+		}
+		again, _, _ := cal.Kigo(saijiki, now, nil)
+		if again != k {
+			t.Errorf("expected the same word on a rerun, got %s and %s", k, again)
+		}
+	})
+
+	t.Run("doesn't repeat words within a season", func(t *testing.T) {
+		current, _ := cal.Current(now)
+		posted := make(map[time.Time]string)
+		seen := make(map[string]bool)
+		for at := current.Start; ; at = at.AddDate(0, 0, 1) {
+			k, s, err := cal.Kigo(saijiki, at, posted)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s.ID != current.ID {
+				break
+			}
+			if seen[k.Word] {
+				t.Errorf("expected a new word on %s, got %s again", at.Format(time.DateOnly), k)
+			}
+			seen[k.Word] = true
+			posted[at] = k.String()
+		}
+		if len(seen) < 5 {
+			t.Errorf("expected a word for each day of %s, got %d", current.ID, len(seen))
+		}
+	})
+
+	t.Run("doesn't repeat a sekki's words across its kō", func(t *testing.T) {
+		// Risshun 2027 starts on February 4th, and its kō on the 4th, 9th
+		// and 14th.
+		risshun, _ := cal.Only(KindSekki).Current(time.Date(2027, time.February, 10, 12, 0, 0, 0, jst))
+		posted := make(map[time.Time]string)
+		seen := make(map[string]string)
+		ko := make(map[string]bool)
+		for at := risshun.Start.Add(time.Hour); at.Before(risshun.Start.AddDate(0, 0, 15)); at = at.AddDate(0, 0, 1) {
+			k, s, err := cal.Kigo(saijiki, at, posted)
+			if errors.Is(err, ErrNoKigo) {
+				continue
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			ko[s.ID] = true
+			if day, ok := seen[k.Word]; ok {
+				t.Errorf("expected a new word on %s, got %s again from %s", at.Format(time.DateOnly), k, day)
+			}
+			seen[k.Word] = at.Format(time.DateOnly)
+			posted[at] = k.String()
+		}
+		if len(ko) < 2 {
+			t.Errorf("expected words from more than one kō, got %v", ko)
+		}
+	})
+
+	t.Run("posts once a day", func(t *testing.T) {
+		posted := map[time.Time]string{now.Add(-time.Hour): "春寒（はるさむ）"}
+		if _, _, err := cal.Kigo(saijiki, now, posted); !errors.Is(err, ErrAlreadyPosted) {
+			t.Errorf("expected ErrAlreadyPosted, got %v", err)
+		}
+	})
+
+	t.Run("runs out of words", func(t *testing.T) {
+		s, _ := cal.Current(now)
+		posted := make(map[time.Time]string)
+		for i, k := range saijiki.Words(s) {
+			posted[s.Start.Add(time.Duration(i)*time.Minute)] = k.String()
+		}
+		if _, _, err := cal.Kigo(saijiki, now.AddDate(0, 0, 1), posted); !errors.Is(err, ErrNoKigo) {
+			t.Errorf("expected ErrNoKigo, got %v", err)
+		}
+	})
+}
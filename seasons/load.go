@@ -0,0 +1,152 @@
+package seasons
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is the file format of a season dataset.
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+	FormatTOML Format = "toml"
+)
+
+// FormatOf returns the format of a dataset, based on its file extension.
+func FormatOf(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FormatJSON, nil
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	case ".toml":
+		return FormatTOML, nil
+	}
+	return "", fmt.Errorf("unknown season file format: %s", path)
+}
+
+// Default returns a calendar for the embedded sekki dataset.
+func Default(opts ...Option) (*Calendar, error) {
+	return FromProvider(Sekki, opts...)
+}
+
+// Load returns a calendar for the dataset read from r.
+func Load(r io.Reader, format Format, opts ...Option) (*Calendar, error) {
+	defs, err := Decode(r, format)
+	if err != nil {
+		return nil, fmt.Errorf("error loading seasons: %w", err)
+	}
+	return New(defs, opts...)
+}
+
+// LoadFile returns a calendar for the dataset at path. If path is a
+// directory, every JSON, YAML and TOML file in it is loaded, in name order,
+// into a single calendar.
+func LoadFile(path string, opts ...Option) (*Calendar, error) {
+	defs, err := ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return New(defs, opts...)
+}
+
+// ReadFile reads the season definitions at path, which may be a file or a
+// directory of files.
+func ReadFile(path string) ([]Definition, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening seasons: %w", err)
+	}
+	if !info.IsDir() {
+		return readFile(path)
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading seasons directory: %w", err)
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		if _, err := FormatOf(e.Name()); err == nil {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	var defs []Definition
+	for _, name := range names {
+		d, err := readFile(filepath.Join(path, name))
+		if err != nil {
+			return nil, err
+		}
+		defs = append(defs, d...)
+	}
+	return defs, nil
+}
+
+func readFile(path string) ([]Definition, error) {
+	format, err := FormatOf(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening seasons: %w", err)
+	}
+	defer f.Close()
+	defs, err := Decode(f, format)
+	if err != nil {
+		return nil, fmt.Errorf("error loading %s: %w", path, err)
+	}
+	return defs, nil
+}
+
+// Decode reads season definitions in the given format from r. Decoding is
+// strict: fields that don't belong to a Definition are an error rather than
+// being silently dropped.
+func Decode(r io.Reader, format Format) ([]Definition, error) {
+	var defs []Definition
+	switch format {
+	case FormatJSON:
+		dec := json.NewDecoder(r)
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&defs); err != nil {
+			return nil, err
+		}
+	case FormatYAML:
+		dec := yaml.NewDecoder(r)
+		dec.KnownFields(true)
+		if err := dec.Decode(&defs); err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+	case FormatTOML:
+		// TOML documents are tables at the top level, so seasons are listed
+		// as [[season]] entries.
+		var doc struct {
+			Seasons []Definition `toml:"season"`
+		}
+		md, err := toml.NewDecoder(r).Decode(&doc)
+		if err != nil {
+			return nil, err
+		}
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			return nil, fmt.Errorf("unknown field %q", undecoded[0].String())
+		}
+		defs = doc.Seasons
+	default:
+		return nil, fmt.Errorf("unknown season file format: %q", format)
+	}
+	return defs, nil
+}
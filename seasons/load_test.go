@@ -0,0 +1,109 @@
+package seasons
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		input  string
+	}{
+		{"json", FormatJSON, `[{"id": "shunbun", "title": "Vernal equinox", "description": "Spring.", "longitude": 0, "emoji": "🌸"}]`},
+		{"yaml", FormatYAML, "- id: shunbun\n  title: Vernal equinox\n  description: Spring.\n  longitude: 0\n  emoji: \"🌸\"\n"},
+		{"toml", FormatTOML, "[[season]]\nid = \"shunbun\"\ntitle = \"Vernal equinox\"\ndescription = \"Spring.\"\nlongitude = 0\nemoji = \"🌸\"\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defs, err := Decode(strings.NewReader(tt.input), tt.format)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if len(defs) != 1 || defs[0].ID != "shunbun" {
+				t.Fatalf("expected shunbun, got %+v", defs)
+			}
+			if defs[0].Longitude == nil || *defs[0].Longitude != 0 {
+				t.Errorf("expected longitude 0, got %v", defs[0].Longitude)
+			}
+		})
+	}
+
+	t.Run("rejects unknown fields", func(t *testing.T) {
+		inputs := map[Format]string{
+			FormatJSON: `[{"id": "shunbun", "colour": "pink"}]`,
+			FormatYAML: "- id: shunbun\n  colour: pink\n",
+			FormatTOML: "[[season]]\nid = \"shunbun\"\ncolour = \"pink\"\n",
+		}
+		for format, input := range inputs {
+			if _, err := Decode(strings.NewReader(input), format); err == nil {
+				t.Errorf("expected error for %s", format)
+			}
+		}
+	})
+}
+
+func TestValidate(t *testing.T) {
+	lon := func(v float64) *float64 { return &v }
+	valid := Definition{ID: "shunbun", Title: "Vernal equinox", Longitude: lon(0)}
+
+	t.Run("accepts the built-in dataset", func(t *testing.T) {
+		if _, err := Default(); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+
+	tests := []struct {
+		name string
+		defs []Definition
+		want string
+	}{
+		{"empty dataset", nil, "no seasons defined"},
+		{"missing id", []Definition{{Title: "Nameless", Longitude: lon(0)}}, "has no id"},
+		{"duplicate ids", []Definition{valid, {ID: "shunbun", Longitude: lon(15)}}, "duplicate season id"},
+		{"no start", []Definition{{ID: "shunbun"}}, "no longitude or start date"},
+		{"both starts", []Definition{{ID: "shunbun", Longitude: lon(0), StartDate: "03-20"}}, "both a longitude and a start date"},
+		{"bad longitude", []Definition{{ID: "shunbun", Longitude: lon(360)}}, "invalid longitude"},
+		{"bad date", []Definition{{ID: "shunbun", StartDate: "13-40"}}, "invalid start date"},
+		{"same start", []Definition{valid, {ID: "equinox", Longitude: lon(0)}}, "both start at"},
+		{"unknown base season", []Definition{{ID: "setsubun", From: "risshun", Days: -1}}, "dated from unknown season"},
+		{"days without a base season", []Definition{{ID: "setsubun", Longitude: lon(314), Days: -1}}, "isn't dated from another season"},
+		{"unknown kind", []Definition{{ID: "shunbun", Kind: "holiday", Longitude: lon(0)}}, "unknown kind"},
+		{"stray kō", []Definition{
+			{ID: "shunbun", Longitude: lon(0), Ko: []Definition{{ID: "late", Longitude: lon(20)}}},
+			{ID: "seimei", Longitude: lon(15)},
+		}, "starts outside of sekki"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.defs)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.json":    `[{"id": "shunbun", "title": "Vernal equinox", "description": "Spring.", "longitude": 0, "emoji": "🌸"}]`,
+		"b.yaml":    "- id: geshi\n  title: Reaching summer\n  description: Summer.\n  longitude: 90\n  emoji: \"☀️\"\n",
+		"notes.txt": "not seasons",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defs, err := ReadFile(dir)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(defs) != 2 || defs[0].ID != "shunbun" || defs[1].ID != "geshi" {
+		t.Errorf("expected shunbun and geshi, got %+v", defs)
+	}
+}
@@ -0,0 +1,99 @@
+package seasons
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PostTimeMode is the rule used to pick when a season gets posted.
+type PostTimeMode int
+
+const (
+	// PostAtStart posts at the exact moment the season starts.
+	PostAtStart PostTimeMode = iota
+	// PostAtClock posts at a fixed time of day, on the day the season starts.
+	PostAtClock
+	// PostAfterStart posts at a fixed offset from the moment the season starts.
+	PostAfterStart
+)
+
+// PostTime is the policy for when a season should be posted, relative to the
+// moment it starts.
+type PostTime struct {
+	Mode     PostTimeMode
+	Location *time.Location // zone used to decide which day a season starts on
+	Clock    time.Duration  // time of day to post at, for PostAtClock
+	Offset   time.Duration  // delay after the start, for PostAfterStart
+}
+
+// ParsePostTime parses a post time policy. at is either "start" for the exact
+// moment a season starts, a time of day like "16:02", or an offset from the
+// start like "+2h" or "-30m". tz is the IANA name of the zone to use, or empty
+// to use the zone of the calendar the policy is given to.
+func ParsePostTime(at, tz string) (PostTime, error) {
+	var p PostTime
+	if tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			return PostTime{}, fmt.Errorf("loading timezone: %w", err)
+		}
+		p.Location = loc
+	}
+	switch {
+	case at == "start":
+		p.Mode = PostAtStart
+	case strings.HasPrefix(at, "+") || strings.HasPrefix(at, "-"):
+		d, err := time.ParseDuration(at)
+		if err != nil {
+			return PostTime{}, fmt.Errorf("parsing offset %q: %w", at, err)
+		}
+		p.Mode = PostAfterStart
+		p.Offset = d
+	default:
+		t, err := time.Parse("15:04", at)
+		if err != nil {
+			return PostTime{}, fmt.Errorf("parsing time of day %q: %w", at, err)
+		}
+		p.Mode = PostAtClock
+		p.Clock = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	}
+	return p, nil
+}
+
+// At returns the time a season starting at start should be posted.
+func (p PostTime) At(start time.Time) time.Time {
+	switch p.Mode {
+	case PostAtClock:
+		return p.clockOn(start)
+	case PostAfterStart:
+		return start.Add(p.Offset).In(p.Location)
+	default:
+		return start.In(p.Location)
+	}
+}
+
+// clockOn returns the policy's time of day on the day t falls on in its
+// zone. It's built from the wall clock rather than added to midnight, so it
+// stays put on days the clocks change.
+func (p PostTime) clockOn(t time.Time) time.Time {
+	y, m, d := t.In(p.Location).Date()
+	return time.Date(y, m, d, int(p.Clock/time.Hour), int(p.Clock%time.Hour/time.Minute), 0, 0, p.Location)
+}
+
+// Due reports whether a post scheduled for at should go out at now. Posts
+// pinned to the exact astronomical moment never go out early, but a fixed
+// time of day allows an hour of slack so an hourly cron job catches it.
+func (p PostTime) Due(at, now time.Time) bool {
+	if p.Mode == PostAtClock {
+		return at.Sub(now) < time.Hour
+	}
+	return !now.Before(at)
+}
+
+// SameDay reports whether a and b fall on the same day in the policy's zone.
+func (p PostTime) SameDay(a, b time.Time) bool {
+	y1, m1, d1 := a.In(p.Location).Date()
+	y2, m2, d2 := b.In(p.Location).Date()
+	return y1 == y2 && m1 == m2 && d1 == d2
+}
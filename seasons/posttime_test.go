@@ -0,0 +1,73 @@
+package seasons
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPostTime(t *testing.T) {
+	// Risshun 2026 starts at 05:02 JST on February 4th.
+	start := time.Date(2026, time.February, 3, 20, 2, 2, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		at   string
+		tz   string
+		want time.Time
+	}{
+		{"exact start", "start", "Asia/Tokyo", start},
+		{"time of day in Tokyo", "16:02", "Asia/Tokyo", time.Date(2026, time.February, 4, 7, 2, 0, 0, time.UTC)},
+		{"time of day in UTC", "16:02", "UTC", time.Date(2026, time.February, 3, 16, 2, 0, 0, time.UTC)},
+		{"offset from start", "+90m", "Asia/Tokyo", start.Add(90 * time.Minute)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := ParsePostTime(tt.at, tt.tz)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if got := p.At(start); !got.Equal(tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("keeps the time of day when the clocks change", func(t *testing.T) {
+		p, err := ParsePostTime("16:02", "Europe/Berlin")
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Summer time starts in Berlin on March 28th 2027, and ends on
+		// October 31st.
+		for _, day := range []time.Time{
+			time.Date(2027, time.March, 28, 12, 0, 0, 0, p.Location),
+			time.Date(2027, time.October, 31, 12, 0, 0, 0, p.Location),
+		} {
+			got := p.At(day)
+			if got.Hour() != 16 || got.Minute() != 2 || got.Day() != day.Day() {
+				t.Errorf("expected 16:02 on %s, got %v", day.Format(time.DateOnly), got)
+			}
+		}
+	})
+
+	t.Run("rejects invalid policies", func(t *testing.T) {
+		for _, at := range []string{"noon", "25:00", "+2 days"} {
+			if _, err := ParsePostTime(at, "UTC"); err == nil {
+				t.Errorf("expected error for %q", at)
+			}
+		}
+		if _, err := ParsePostTime("16:02", "Mars/Olympus_Mons"); err == nil {
+			t.Error("expected error for unknown timezone")
+		}
+	})
+
+	t.Run("never posts early at the exact start", func(t *testing.T) {
+		p, _ := ParsePostTime("start", "Asia/Tokyo")
+		if p.Due(start, start.Add(-time.Minute)) {
+			t.Error("expected post not to be due before the start")
+		}
+		if !p.Due(start, start) {
+			t.Error("expected post to be due at the start")
+		}
+	})
+}
@@ -0,0 +1,68 @@
+package seasons
+
+import "time"
+
+// PreviewTemplate is the template for posts announcing a season ahead of
+// time, which renders posts like "In three days: Rain waters 🌧".
+const PreviewTemplate = "{{if eq .Days 1}}Tomorrow{{else}}In {{count .Days}} days{{end}}: {{.Title}} {{.Emoji}}"
+
+// PreviewTemplates are the preview templates for each locale in the built-in
+// catalog, to be filled with the season's translated title.
+var PreviewTemplates = map[string]string{
+	English:  PreviewTemplate,
+	Japanese: "{{if eq .Days 1}}明日{{else}}{{.Days}}日後{{end}}：{{.Title}} {{.Emoji}}",
+	Chinese:  "{{if eq .Days 1}}明天{{else}}{{.Days}}天后{{end}}：{{.Title}} {{.Emoji}}",
+	"de":     "{{if eq .Days 1}}Morgen{{else}}In {{.Days}} Tagen{{end}}: {{.Title}} {{.Emoji}}",
+	"fr":     "{{if eq .Days 1}}Demain{{else}}Dans {{.Days}} jours{{end}} : {{.Title}} {{.Emoji}}",
+	"ko":     "{{if eq .Days 1}}내일{{else}}{{.Days}}일 후{{end}}: {{.Title}} {{.Emoji}}",
+}
+
+// PreviewData is the data available to the preview template.
+type PreviewData struct {
+	Season
+	Days int // days until the season is posted
+}
+
+// Previews returns the seasons to announce days ahead of time at now: those
+// whose post is due days after today's post time. A kō that starts together
+// with its sekki is left out, since the sekki's preview announces the day.
+func (c *Calendar) Previews(now time.Time, days int) []Season {
+	if days <= 0 {
+		return nil
+	}
+	schedule := c.Schedule(now)
+	sekki := make(map[int64]bool)
+	for _, s := range schedule {
+		if s.Kind == KindSekki {
+			sekki[s.Start.Unix()] = true
+		}
+	}
+	var previews []Season
+	for _, s := range schedule {
+		if s.Kind == KindKo && sekki[s.Start.Unix()] {
+			continue
+		}
+		at := s.Date.AddDate(0, 0, -days)
+		if c.postTime.SameDay(at, now) && c.postTime.Due(at, now) {
+			previews = append(previews, s)
+		}
+	}
+	return previews
+}
+
+// Previewed returns the seasons that may have been announced days ahead of
+// time in the month before now, or will be by the end of today, so preview
+// posts can be told apart from season posts.
+func (c *Calendar) Previewed(now time.Time, days int) []Season {
+	if days <= 0 {
+		return nil
+	}
+	var previewed []Season
+	from, to := now.AddDate(0, -1, 0), now.AddDate(0, 0, days+1)
+	for _, s := range c.Schedule(now) {
+		if s.Date.After(from) && s.Date.Before(to) {
+			previewed = append(previewed, s)
+		}
+	}
+	return previewed
+}
@@ -0,0 +1,60 @@
+package seasons
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPreviews(t *testing.T) {
+	postTime, err := ParsePostTime("16:02", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cal, err := Default(WithPostTime(postTime))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Usui 2026 is posted at 16:02 on February 19th, along with its first kō.
+	t.Run("announces seasons days ahead", func(t *testing.T) {
+		previews := cal.Previews(time.Date(2026, time.February, 16, 16, 30, 0, 0, jst), 3)
+		if len(previews) != 1 || previews[0].ID != "usui" {
+			t.Errorf("expected usui, got %v", previews)
+		}
+	})
+
+	t.Run("waits for the post time", func(t *testing.T) {
+		if previews := cal.Previews(time.Date(2026, time.February, 16, 9, 0, 0, 0, jst), 3); len(previews) > 0 {
+			t.Errorf("expected no previews, got %v", previews)
+		}
+		if previews := cal.Previews(time.Date(2026, time.February, 17, 16, 30, 0, 0, jst), 3); len(previews) > 0 {
+			t.Errorf("expected no previews the day after, got %v", previews)
+		}
+	})
+
+	t.Run("renders a countdown", func(t *testing.T) {
+		usui := cal.Previews(time.Date(2026, time.February, 16, 16, 30, 0, 0, jst), 3)[0]
+		tmpl := MustParseTemplate("preview", PreviewTemplate)
+		for days, want := range map[int]string{1: "Tomorrow: Rain waters 🌧", 3: "In three days: Rain waters 🌧", 14: "In 14 days: Rain waters 🌧"} {
+			got, err := tmpl.Execute(PreviewData{Season: usui, Days: days})
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if got != want {
+				t.Errorf("expected %q, got %q", want, got)
+			}
+		}
+	})
+
+	t.Run("has a template for every locale", func(t *testing.T) {
+		c, err := DefaultCatalog()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, locale := range c.Locales() {
+			if _, ok := PreviewTemplates[locale]; !ok {
+				t.Errorf("expected a preview template for %s", locale)
+			}
+		}
+	})
+}
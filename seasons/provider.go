@@ -0,0 +1,97 @@
+package seasons
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"strings"
+	"time"
+)
+
+//go:embed sekki.json zassetsu.json gosekku.json jieqi.json jeolgi.json wheel.json
+var data embed.FS
+
+// Provider supplies the seasons of one calendar tradition, along with the
+// rules for dating them.
+type Provider interface {
+	// Name is the name the calendar is selected by.
+	Name() string
+	// Definitions returns the calendar's seasons.
+	Definitions() ([]Definition, error)
+	// Location returns the zone the calendar is kept in, which decides the
+	// day each season starts on.
+	Location() *time.Location
+}
+
+var (
+	// Sekki is the Japanese calendar of 24 sekki and 72 kō, along with the
+	// zassetsu days of the almanac and the five seasonal festivals, kept in
+	// Japan Standard Time.
+	Sekki Provider = &dataset{name: "sekki", files: []string{"sekki.json", "zassetsu.json", "gosekku.json"}, loc: jst}
+	// Jieqi is the Chinese calendar of 24 solar terms, kept in China Standard
+	// Time.
+	Jieqi Provider = &dataset{name: "jieqi", files: []string{"jieqi.json"}, loc: time.FixedZone("CST", 8*60*60)}
+	// Jeolgi is the Korean calendar of 24 solar terms, kept in Korea Standard
+	// Time.
+	Jeolgi Provider = &dataset{name: "jeolgi", files: []string{"jeolgi.json"}, loc: time.FixedZone("KST", 9*60*60)}
+	// Wheel is the Wheel of the Year: the solstices and equinoxes, and the
+	// Celtic cross-quarter festivals on their traditional dates, kept in UTC.
+	Wheel Provider = &dataset{name: "wheel", files: []string{"wheel.json"}, loc: time.UTC}
+)
+
+// Providers returns every built-in calendar.
+func Providers() []Provider {
+	return []Provider{Sekki, Jieqi, Jeolgi, Wheel}
+}
+
+// LookupProvider returns the built-in calendar with the given name.
+func LookupProvider(name string) (Provider, error) {
+	var names []string
+	for _, p := range Providers() {
+		if p.Name() == name {
+			return p, nil
+		}
+		names = append(names, p.Name())
+	}
+	return nil, fmt.Errorf("unknown calendar %q: must be one of %s", name, strings.Join(names, ", "))
+}
+
+// FromProvider returns a calendar for the seasons of p.
+func FromProvider(p Provider, opts ...Option) (*Calendar, error) {
+	defs, err := p.Definitions()
+	if err != nil {
+		return nil, fmt.Errorf("error loading %s: %w", p.Name(), err)
+	}
+	c, err := New(defs, append([]Option{WithLocation(p.Location())}, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+	c.name = p.Name()
+	return c, nil
+}
+
+// dataset is a Provider for a set of the embedded season files.
+type dataset struct {
+	name  string
+	files []string
+	loc   *time.Location
+}
+
+func (d dataset) Name() string             { return d.name }
+func (d dataset) Location() *time.Location { return d.loc }
+
+func (d dataset) Definitions() ([]Definition, error) {
+	var defs []Definition
+	for _, file := range d.files {
+		b, err := data.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		decoded, err := Decode(bytes.NewReader(b), FormatJSON)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		defs = append(defs, decoded...)
+	}
+	return defs, nil
+}
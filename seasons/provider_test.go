@@ -0,0 +1,47 @@
+package seasons
+
+import "testing"
+
+func TestProviders(t *testing.T) {
+	for _, p := range Providers() {
+		t.Run("loads "+p.Name(), func(t *testing.T) {
+			if _, err := FromProvider(p); err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+		})
+	}
+
+	t.Run("looks up calendars by name", func(t *testing.T) {
+		p, err := LookupProvider("jeolgi")
+		if err != nil || p != Jeolgi {
+			t.Errorf("expected jeolgi, got %v %v", p, err)
+		}
+		if _, err := LookupProvider("mayan"); err == nil {
+			t.Error("expected an error for an unknown calendar")
+		}
+	})
+
+	t.Run("dates terms in the calendar's own zone", func(t *testing.T) {
+		// The rain water term starts at 15:51 UTC on February 18th 2026, which
+		// is still the 18th in Beijing but already the 19th in Tokyo.
+		day := func(p Provider, id string) int {
+			cal, err := FromProvider(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range cal.Year(2026) {
+				if s.ID == id {
+					return s.Start.In(cal.PostTime().Location).Day()
+				}
+			}
+			t.Fatalf("no %s in 2026", id)
+			return 0
+		}
+		if got := day(Jieqi, "yushui"); got != 18 {
+			t.Errorf("expected yushui on the 18th, got %v", got)
+		}
+		if got := day(Sekki, "usui"); got != 19 {
+			t.Errorf("expected usui on the 19th, got %v", got)
+		}
+	})
+}
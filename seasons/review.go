@@ -0,0 +1,37 @@
+package seasons
+
+import "time"
+
+// ReviewTemplate is the template for the first post of the year in review
+// thread, which renders posts like "Looking back on 2026, season by season 🧵".
+const ReviewTemplate = "Looking back on {{.Year}}, season by season 🧵"
+
+// ReviewEntryTemplate is the template for each season in the year in review
+// thread, which renders posts like "Start of spring 🌸 · February 4".
+const ReviewEntryTemplate = "{{.Title}} {{.Emoji}} · {{date .Date}}"
+
+// ReviewData is the data available to the review template.
+type ReviewData struct {
+	Year    int
+	Seasons []Season
+}
+
+// Review returns the year to look back on at now, and the top-level seasons
+// posted in it, in order. It's due on New Year's Eve, from the post time on.
+func (c *Calendar) Review(now time.Time) (int, []Season, bool) {
+	loc := c.postTime.Location
+	y, m, d := now.In(loc).Date()
+	if m != time.December || d != 31 {
+		return 0, nil, false
+	}
+	if !c.postTime.Due(c.postTime.At(time.Date(y, m, d, 0, 0, 0, 0, loc)), now) {
+		return 0, nil, false
+	}
+	var seasons []Season
+	for _, s := range c.Only(KindSekki).Year(y) {
+		if s.Date.In(loc).Year() == y {
+			seasons = append(seasons, s)
+		}
+	}
+	return y, seasons, true
+}
@@ -0,0 +1,52 @@
+package seasons
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReview(t *testing.T) {
+	postTime, err := ParsePostTime("16:02", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cal, err := Default(WithPostTime(postTime))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("looks back on New Year's Eve", func(t *testing.T) {
+		year, seasons, ok := cal.Review(time.Date(2026, time.December, 31, 16, 30, 0, 0, jst))
+		if !ok || year != 2026 {
+			t.Fatalf("expected a review of 2026, got %d %v", year, ok)
+		}
+		if len(seasons) != 24 {
+			t.Errorf("expected 24 seasons, got %d", len(seasons))
+		}
+		if seasons[0].ID != "shokan" || seasons[len(seasons)-1].ID != "toji" {
+			t.Errorf("expected shokan to toji, got %s to %s", seasons[0].ID, seasons[len(seasons)-1].ID)
+		}
+	})
+
+	t.Run("waits for New Year's Eve and the post time", func(t *testing.T) {
+		for _, now := range []time.Time{
+			time.Date(2026, time.December, 30, 16, 30, 0, 0, jst),
+			time.Date(2026, time.December, 31, 9, 0, 0, 0, jst),
+		} {
+			if _, _, ok := cal.Review(now); ok {
+				t.Errorf("expected no review at %v", now)
+			}
+		}
+	})
+
+	t.Run("lists each season by date", func(t *testing.T) {
+		_, seasons, _ := cal.Review(time.Date(2026, time.December, 31, 16, 30, 0, 0, jst))
+		got, err := MustParseTemplate("review-entry", ReviewEntryTemplate).Execute(seasons[3])
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if want := "Rain waters 🌧 · February 19"; got != want {
+			t.Errorf("expected %q, got %q", want, got)
+		}
+	})
+}
@@ -0,0 +1,194 @@
+// Package seasons schedules the Japanese seasonal calendar: the 24 sekki
+// (solar terms) and the 72 kō (microseasons) that divide them. It can answer
+// which season is in effect at any moment, and which season is due to be
+// posted. Other calendars, like the Chinese and Korean solar terms, are
+// available as Providers.
+package seasons
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	"github.com/rosszurowski/small-seasons-bot/astro"
+)
+
+var (
+	ErrAlreadyPosted = errors.New("already posted")
+	ErrNoSeason      = errors.New("no season to post")
+)
+
+// Kind is the kind of season being posted.
+type Kind string
+
+const (
+	KindSekki    Kind = "sekki"    // a top-level season, like one of the 24 solar terms
+	KindKo       Kind = "ko"       // one of the 72 microseasons
+	KindZassetsu Kind = "zassetsu" // a miscellaneous seasonal day from the almanac, like setsubun
+	KindSekku    Kind = "sekku"    // one of the five seasonal festivals, like tanabata
+)
+
+// Hemisphere is the half of the world a calendar is kept for.
+type Hemisphere string
+
+const (
+	North Hemisphere = "north"
+	South Hemisphere = "south"
+)
+
+// ParseHemisphere parses a hemisphere name, either "north" or "south".
+func ParseHemisphere(s string) (Hemisphere, error) {
+	switch h := Hemisphere(s); h {
+	case North, South:
+		return h, nil
+	}
+	return "", fmt.Errorf("invalid hemisphere %q: must be north or south", s)
+}
+
+// Definition is a season as described in a dataset, before it's dated. A
+// season starts either when the sun reaches Longitude, on the fixed StartDate
+// each year, or a number of Days after the day another season From starts.
+type Definition struct {
+	ID                  string       `json:"id" yaml:"id" toml:"id"`
+	Kind                Kind         `json:"kind,omitempty" yaml:"kind,omitempty" toml:"kind,omitempty"` // sekki unless set; only top-level seasons can set it
+	Title               string       `json:"title" yaml:"title" toml:"title"`
+	Japanese            string       `json:"japanese,omitempty" yaml:"japanese,omitempty" toml:"japanese,omitempty"`
+	Reading             string       `json:"reading,omitempty" yaml:"reading,omitempty" toml:"reading,omitempty"` // kana reading of the Japanese title
+	Native              string       `json:"native,omitempty" yaml:"native,omitempty" toml:"native,omitempty"`    // name in the calendar's own language, for calendars other than the Japanese one
+	Description         string       `json:"description" yaml:"description" toml:"description"`
+	JapaneseDescription string       `json:"japaneseDescription,omitempty" yaml:"japaneseDescription,omitempty" toml:"japaneseDescription,omitempty"`
+	Variants            []string     `json:"variants,omitempty" yaml:"variants,omitempty" toml:"variants,omitempty"`    // alternative descriptions, rotated through year by year
+	Longitude           *float64     `json:"longitude,omitempty" yaml:"longitude,omitempty" toml:"longitude,omitempty"` // ecliptic longitude of the sun when the season starts
+	StartDate           string       `json:"startDate,omitempty" yaml:"startDate,omitempty" toml:"startDate,omitempty"` // fixed start date, formatted as MM-DD
+	From                string       `json:"from,omitempty" yaml:"from,omitempty" toml:"from,omitempty"`                // ID of the season this one is dated from
+	Days                int          `json:"days,omitempty" yaml:"days,omitempty" toml:"days,omitempty"`                // days after From starts, or before it if negative
+	Emoji               string       `json:"emoji" yaml:"emoji" toml:"emoji"`
+	Ko                  []Definition `json:"ko,omitempty" yaml:"ko,omitempty" toml:"ko,omitempty"` // the three kō (microseasons) of a sekki
+}
+
+// Descriptions returns every variant of the season's description, starting
+// with the main one.
+func (d Definition) Descriptions() []string {
+	return append([]string{d.Description}, d.Variants...)
+}
+
+// DescriptionFor returns the variant of the season's description to use in the
+// given year. Variants are rotated through one year at a time, starting from
+// a point picked from the season's ID, so the text changes every year without
+// every season switching to the same variant at once.
+func (d Definition) DescriptionFor(year int) (description string, variant int) {
+	descriptions := d.Descriptions()
+	h := fnv.New32a()
+	h.Write([]byte(d.ID))
+	variant = (int(h.Sum32()%uint32(len(descriptions))) + year) % len(descriptions)
+	if variant < 0 {
+		variant += len(descriptions)
+	}
+	return descriptions[variant], variant
+}
+
+type Season struct {
+	ID                  string
+	Kind                Kind
+	Calendar            string    // name of the built-in calendar the season is from, if any
+	Parent              string    // ID of the sekki a kō belongs to
+	Start               time.Time // exact moment the season starts
+	Date                time.Time // date to post the post at
+	Title               string
+	Japanese            string
+	Reading             string // kana reading of the Japanese title
+	Native              string // name in the calendar's own language, falling back to the Japanese
+	Description         string
+	JapaneseDescription string
+	Variant             int // which of the definition's descriptions is used
+	Emoji               string
+}
+
+// Marker returns the machine-readable tag posts of the season carry, so the
+// bot can tell which seasons it has posted, like "sekki:risshun:2026". The
+// top-level seasons of the other built-in calendars aren't sekki, so they're
+// marked with the calendar's name instead, like "jieqi:lichun:2027".
+func (s Season) Marker() string {
+	kind := string(s.Kind)
+	if s.Kind == KindSekki && s.Calendar != "" && s.Calendar != Sekki.Name() {
+		kind = s.Calendar
+	}
+	return fmt.Sprintf("%s:%s:%d", kind, s.ID, s.Date.Year())
+}
+
+// Hashtag returns the season's marker as a hashtag, without the #, for
+// platforms that can only carry it in the text of a post, like
+// "sekki_risshun_2026".
+func (s Season) Hashtag() string {
+	return strings.NewReplacer(":", "_", "-", "_").Replace(s.Marker())
+}
+
+// Option configures a Calendar.
+type Option func(*Calendar)
+
+// SameDay is the policy for seasons that are posted on the same day.
+type SameDay string
+
+const (
+	// SameDayInOrder posts each season separately, one after another, in
+	// priority order.
+	SameDayInOrder SameDay = "order"
+	// SameDayCombine posts every season of the day together in one post.
+	SameDayCombine SameDay = "combine"
+)
+
+// ParseSameDay parses a same-day policy, either "order" or "combine".
+func ParseSameDay(s string) (SameDay, error) {
+	switch p := SameDay(s); p {
+	case SameDayInOrder, SameDayCombine:
+		return p, nil
+	}
+	return "", fmt.Errorf("invalid same-day policy %q: must be order or combine", s)
+}
+
+// WithSameDay returns an Option that sets how seasons posted on the same day
+// are handled. By default they're posted in order.
+func WithSameDay(p SameDay) Option {
+	return func(c *Calendar) {
+		c.sameDay = p
+	}
+}
+
+// WithPlace returns an Option that sets where the sunrise and sunset given to
+// post templates are seen from. By default it's Tokyo.
+func WithPlace(p astro.Place) Option {
+	return func(c *Calendar) {
+		c.place = p
+	}
+}
+
+// WithLocation returns an Option that sets the zone the calendar is kept in,
+// which decides the day seasons start on unless the post time sets its own.
+// Calendars from a Provider are kept in the provider's zone.
+func WithLocation(loc *time.Location) Option {
+	return func(c *Calendar) {
+		c.location = loc
+	}
+}
+
+// WithPostTime returns an Option that sets the policy used to decide when
+// each season is posted. By default seasons are posted at the exact moment
+// they start, in Japan Standard Time.
+func WithPostTime(p PostTime) Option {
+	return func(c *Calendar) {
+		c.postTime = p
+	}
+}
+
+// WithHemisphere returns an Option that sets the hemisphere the calendar is
+// kept for. The southern hemisphere's seasons are six months out of phase
+// with the northern ones the datasets describe, so each season there starts
+// when the sun is opposite its longitude, or six months after its fixed
+// date. By default the calendar is kept for the northern hemisphere.
+func WithHemisphere(h Hemisphere) Option {
+	return func(c *Calendar) {
+		c.hemisphere = h
+	}
+}
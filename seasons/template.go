@@ -0,0 +1,146 @@
+package seasons
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/rosszurowski/small-seasons-bot/astro"
+)
+
+// DefaultTemplate is the post template used when none is configured, which
+// renders posts like "Rain waters. Snow melts away… 🌧". Calendars other than
+// the Japanese one have the season's name in their own language after the
+// title, like "Start of spring (立春). The east wind…".
+const DefaultTemplate = "{{.Title}}{{if and .Native (ne .Native .Japanese) (ne .Native .Title)}} ({{.Native}}){{end}}. {{.Description}} {{.Emoji}}"
+
+// AlmanacTemplate is DefaultTemplate followed by a line with the moon phase
+// and the day's sunrise and sunset.
+const AlmanacTemplate = DefaultTemplate + "\n\n" +
+	"{{.Moon.Emoji}} {{.Moon.Name}} · 🌅 {{clock .Sunrise}} · 🌇 {{clock .Sunset}} · {{duration .DayLength}} of daylight"
+
+// JapaneseTemplate renders the Japanese text of a season, for posting
+// alongside the English, like "雨水（うすい）" followed by its description.
+const JapaneseTemplate = "{{.Japanese}}{{with .Reading}}（{{.}}）{{end}}\n{{.JapaneseDescription}}"
+
+// ChineseTemplate is the default template for posts in Chinese, like
+// "立春：东风解冻… 🌱".
+const ChineseTemplate = "{{.Title}}：{{.Description}} {{.Emoji}}"
+
+// funcs are the functions available to post templates.
+var funcs = template.FuncMap{
+	// clock formats a time as a 24-hour time of day, like "16:02".
+	"clock": func(t time.Time) string {
+		return t.Format("15:04")
+	},
+	// count spells out numbers up to ten, like "three", and writes larger
+	// ones as digits.
+	"count": func(n int) string {
+		if n >= 0 && n < len(numbers) {
+			return numbers[n]
+		}
+		return strconv.Itoa(n)
+	},
+	// date formats a time as a month and day, like "February 4".
+	"date": func(t time.Time) string {
+		return t.Format("January 2")
+	},
+	// duration formats a duration in hours and minutes, like "14h 35m".
+	"duration": func(d time.Duration) string {
+		d = d.Round(time.Minute)
+		return fmt.Sprintf("%dh %02dm", int(d.Hours()), int(d.Minutes())%60)
+	},
+	// weekday formats a time as the day of the week, like "Tuesday".
+	"weekday": func(t time.Time) string {
+		return t.Format("Monday")
+	},
+}
+
+// numbers are the numbers the count template function spells out.
+var numbers = []string{"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "ten"}
+
+// Template renders the text of a season post using text/template.
+type Template struct {
+	tmpl *template.Template
+}
+
+// TemplateData is the data available to a post template. Every field of the
+// season being posted can be used directly, as in {{.Title}} or
+// {{.Japanese}}, alongside a few values computed for the post.
+type TemplateData struct {
+	Season
+	Year      int    // year the season is posted in
+	DayOfYear int    // day of the year the season is posted on
+	Next      Season // the next season of the same kind
+
+	// The sky on the day the season is posted, as seen from the calendar's
+	// place. Sunrise and Sunset are zero on days the sun doesn't rise or set.
+	Moon      astro.MoonPhase // phase of the moon at the post time
+	Sunrise   time.Time
+	Sunset    time.Time
+	DayLength time.Duration
+}
+
+// ParseTemplate parses a post template.
+func ParseTemplate(name, text string) (*Template, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Funcs(funcs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parsing template: %w", err)
+	}
+	return &Template{tmpl: tmpl}, nil
+}
+
+// ParseTemplateFile parses the post template in the file at path.
+func ParseTemplateFile(path string) (*Template, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading template: %w", err)
+	}
+	return ParseTemplate(filepath.Base(path), string(b))
+}
+
+// MustParseTemplate is like ParseTemplate, but panics if the template can't
+// be parsed.
+func MustParseTemplate(name, text string) *Template {
+	t, err := ParseTemplate(name, text)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Data returns the template data for posting s from the calendar.
+func (c *Calendar) Data(s Season) TemplateData {
+	date := s.Date.In(c.postTime.Location)
+	next, _ := c.nextOfKind(s)
+	sunrise, sunset, _ := astro.SunTimes(date, c.place)
+	return TemplateData{
+		Season:    s,
+		Year:      date.Year(),
+		DayOfYear: date.YearDay(),
+		Next:      next,
+		Moon:      astro.Moon(s.Date),
+		Sunrise:   sunrise,
+		Sunset:    sunset,
+		DayLength: sunset.Sub(sunrise),
+	}
+}
+
+// Execute renders the template with data. Leading and trailing whitespace is
+// trimmed, so template files can end with a newline.
+func (t *Template) Execute(data any) (string, error) {
+	var b strings.Builder
+	if err := t.tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("rendering template: %w", err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// Render renders the post for season s from the calendar.
+func (t *Template) Render(cal *Calendar, s Season) (string, error) {
+	return t.Execute(cal.Data(s))
+}
@@ -0,0 +1,97 @@
+package seasons
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTemplate(t *testing.T) {
+	cal, err := Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	usui, ok := cal.Only(KindSekki).Current(time.Date(2026, time.February, 20, 0, 0, 0, 0, jst))
+	if !ok {
+		t.Fatal("expected a season")
+	}
+
+	t.Run("default matches the original format", func(t *testing.T) {
+		got, err := MustParseTemplate("default", DefaultTemplate).Render(cal, usui)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		want := "Rain waters. Snow melts away, mist lingers in the air, and grasses begin to sprout. Trees release their first buds as the ground fills with water. 🌧"
+		if got != want {
+			t.Errorf("expected %q, got %q", want, got)
+		}
+	})
+
+	t.Run("default adds the native name for other calendars", func(t *testing.T) {
+		jieqi, err := FromProvider(Jieqi)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lichun, ok := jieqi.Current(time.Date(2026, time.February, 10, 0, 0, 0, 0, jst))
+		if !ok {
+			t.Fatal("expected a season")
+		}
+		got, err := MustParseTemplate("default", DefaultTemplate).Render(jieqi, lichun)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if want := "Start of spring (立春). The east wind"; !strings.HasPrefix(got, want) {
+			t.Errorf("expected %q to start with %q", got, want)
+		}
+	})
+
+	t.Run("exposes computed values", func(t *testing.T) {
+		tmpl := MustParseTemplate("custom", "{{.Japanese}} ({{.Year}}, day {{.DayOfYear}}). Next up: {{.Next.Title}}.\n")
+		got, err := tmpl.Render(cal, usui)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		want := "雨水 (2026, day 50). Next up: Going-out of the insects."
+		if got != want {
+			t.Errorf("expected %q, got %q", want, got)
+		}
+	})
+
+	t.Run("describes the sky on the day", func(t *testing.T) {
+		// Usui 2026 starts two days after the new moon of February 17th.
+		data := cal.Data(usui)
+		if data.Moon.Name != "Waxing crescent" {
+			t.Errorf("expected a waxing crescent, got %v", data.Moon.Name)
+		}
+		if data.Sunrise.Day() != 19 || data.Sunrise.Hour() != 6 {
+			t.Errorf("expected sunrise around 6am on the 19th, got %v", data.Sunrise)
+		}
+		if data.DayLength < 11*time.Hour || data.DayLength > 11*time.Hour+15*time.Minute {
+			t.Errorf("expected about 11 hours of daylight, got %v", data.DayLength)
+		}
+		got, err := MustParseTemplate("almanac", AlmanacTemplate).Render(cal, usui)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if want := "🌒 Waxing crescent · 🌅 06:"; !strings.Contains(got, want) {
+			t.Errorf("expected %q in %q", want, got)
+		}
+	})
+
+	t.Run("renders the Japanese text", func(t *testing.T) {
+		got, err := MustParseTemplate("japanese", JapaneseTemplate).Render(cal, usui)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if want := "雨水（うすい）\n"; !strings.HasPrefix(got, want) {
+			t.Errorf("expected %q to start with %q", got, want)
+		}
+	})
+
+	t.Run("rejects unknown fields", func(t *testing.T) {
+		tmpl := MustParseTemplate("bad", "{{.Colour}}")
+		if _, err := tmpl.Render(cal, usui); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
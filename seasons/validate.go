@@ -0,0 +1,157 @@
+package seasons
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// startDateLayout is the layout of Definition.StartDate.
+const startDateLayout = "01-02"
+
+// Validate checks a set of season definitions, returning every problem it
+// finds joined into one error. An empty dataset, missing or duplicate IDs,
+// unknown kinds, empty description variants, seasons without exactly one of a
+// longitude, start date or season they're dated from, unparsable or
+// out-of-range starts, and seasons that start at the same point as another
+// are all errors.
+func Validate(defs []Definition) error {
+	if len(defs) == 0 {
+		return errors.New("no seasons defined")
+	}
+	var errs []error
+	ids := make(map[string]bool)
+	var all []Definition
+	byID := make(map[string]Definition)
+	check := func(d Definition) {
+		all = append(all, d)
+		byID[d.ID] = d
+		if d.ID == "" {
+			errs = append(errs, fmt.Errorf("season %q has no id", d.Title))
+		} else if ids[d.ID] {
+			errs = append(errs, fmt.Errorf("duplicate season id %q", d.ID))
+		}
+		ids[d.ID] = true
+		if err := validateStart(d); err != nil {
+			errs = append(errs, err)
+		}
+		for i, v := range d.Variants {
+			if v == "" {
+				errs = append(errs, fmt.Errorf("season %q has an empty variant %d", d.ID, i+1))
+			}
+		}
+	}
+	for _, d := range defs {
+		check(d)
+		switch d.Kind {
+		case "", KindSekki, KindZassetsu, KindSekku:
+		default:
+			errs = append(errs, fmt.Errorf("season %q has unknown kind %q", d.ID, d.Kind))
+		}
+		for _, k := range d.Ko {
+			check(k)
+			if k.Kind != "" {
+				errs = append(errs, fmt.Errorf("kō %q can't set a kind", k.ID))
+			}
+		}
+	}
+	for _, d := range all {
+		if d.From == "" {
+			continue
+		}
+		if from, ok := byID[d.From]; !ok {
+			errs = append(errs, fmt.Errorf("season %q is dated from unknown season %q", d.ID, d.From))
+		} else if from.From != "" {
+			errs = append(errs, fmt.Errorf("season %q is dated from %q, which is itself dated from another season", d.ID, d.From))
+		}
+	}
+	errs = append(errs, overlaps(defs)...)
+	for _, d := range defs {
+		errs = append(errs, overlaps(d.Ko)...)
+	}
+	errs = append(errs, stray(defs)...)
+	return errors.Join(errs...)
+}
+
+func validateStart(d Definition) error {
+	switch {
+	case d.Longitude != nil && d.StartDate != "":
+		return fmt.Errorf("season %q has both a longitude and a start date", d.ID)
+	case d.From != "" && (d.Longitude != nil || d.StartDate != ""):
+		return fmt.Errorf("season %q is dated from %q but also has a longitude or start date", d.ID, d.From)
+	case d.Days != 0 && d.From == "":
+		return fmt.Errorf("season %q has days but isn't dated from another season", d.ID)
+	case d.Longitude != nil:
+		if lon := *d.Longitude; lon < 0 || lon >= 360 || math.IsNaN(lon) {
+			return fmt.Errorf("invalid longitude for %s: %v", d.ID, lon)
+		}
+	case d.StartDate != "":
+		// Parse against a leap year, so February 29th is allowed.
+		if _, err := time.Parse("2006-"+startDateLayout, "2000-"+d.StartDate); err != nil {
+			return fmt.Errorf("invalid start date for %s: %q", d.ID, d.StartDate)
+		}
+	case d.From != "":
+		// Checked against the other seasons in Validate.
+	default:
+		return fmt.Errorf("season %q has no longitude or start date, and isn't dated from another season", d.ID)
+	}
+	return nil
+}
+
+// overlaps returns an error for each season that starts at the same point as
+// an earlier one in defs.
+func overlaps(defs []Definition) []error {
+	var errs []error
+	seen := make(map[string]string)
+	for _, d := range defs {
+		var key string
+		switch {
+		case d.Longitude != nil:
+			key = fmt.Sprintf("longitude %v", *d.Longitude)
+		case d.StartDate != "":
+			key = "date " + d.StartDate
+		case d.From != "":
+			key = fmt.Sprintf("%+d days from %s", d.Days, d.From)
+		default:
+			continue
+		}
+		if other, ok := seen[key]; ok {
+			errs = append(errs, fmt.Errorf("seasons %q and %q both start at %s", other, d.ID, key))
+			continue
+		}
+		seen[key] = d.ID
+	}
+	return errs
+}
+
+// stray returns an error for each kō whose longitude falls outside the span of
+// its sekki, up until the next sekki begins. Seasons of other kinds don't end
+// a sekki's span.
+func stray(defs []Definition) []error {
+	var errs []error
+	for _, d := range defs {
+		if d.Longitude == nil {
+			continue
+		}
+		// The sekki's span runs until the closest sekki after it.
+		span := 360.0
+		for _, other := range defs {
+			if other.ID == d.ID || other.Longitude == nil || (other.Kind != "" && other.Kind != KindSekki) {
+				continue
+			}
+			if diff := math.Mod(*other.Longitude-*d.Longitude+360, 360); diff > 0 && diff < span {
+				span = diff
+			}
+		}
+		for _, k := range d.Ko {
+			if k.Longitude == nil {
+				continue
+			}
+			if diff := math.Mod(*k.Longitude-*d.Longitude+360, 360); diff >= span {
+				errs = append(errs, fmt.Errorf("kō %q starts outside of sekki %q", k.ID, d.ID))
+			}
+		}
+	}
+	return errs
+}
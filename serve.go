@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/rosszurowski/small-seasons-bot/ledger"
+	"github.com/rosszurowski/small-seasons-bot/seasons"
+)
+
+// maxSleep caps how long serve sleeps at a time, so it notices within the hour
+// if the clock jumps, say after the machine wakes from sleep or is corrected.
+const maxSleep = time.Hour
+
+// retryDelay is how long serve waits to run again after a run fails, say
+// because a platform was down.
+const retryDelay = 10 * time.Minute
+
+// serve keeps running on clk, posting whenever the calendar has something
+// due, until ctx is cancelled or the process gets SIGTERM or an interrupt. A
+// post that's under way is finished before it returns. SIGHUP reloads the
+// season data and templates, using opts for the calendar.
+func serve(ctx context.Context, clk clock, cal *seasons.Calendar, tmpls templates, led *ledger.Ledger, opts []seasons.Option) error {
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGTERM, os.Interrupt)
+	defer stop()
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	// Wake for previews as they come due on either platform, not just for
+	// season posts.
+	var days []int
+	for _, prefix := range []string{"BSKY", "MASTODON"} {
+		n, err := previewDays(prefix)
+		if err != nil {
+			return err
+		}
+		days = append(days, n)
+	}
+
+	// Run straight away, to post anything that came due while stopped.
+	next := clk.Now()
+	for {
+		// Compare wall clock times, so a jump in the clock is picked up on
+		// the next wake.
+		now := clk.Now().Round(0)
+		if !now.Before(next) {
+			// Posts made before the run was cut short wouldn't be picked up
+			// from the account as reliably, so let a run finish.
+			next = cal.NextRun(now, days...)
+			if err := post(context.WithoutCancel(ctx), cal, tmpls, led, now); err != nil {
+				log.Printf("serve: %v", err)
+				if retry := now.Add(retryDelay); retry.Before(next) {
+					next = retry
+				}
+			}
+			log.Printf("serve: next run at %s", next.Format(time.RFC3339))
+		}
+		timer := time.NewTimer(min(next.Sub(now), maxSleep))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			log.Println("serve: shutting down")
+			return nil
+		case <-hup:
+			timer.Stop()
+			log.Println("serve: reloading seasons and templates")
+			if reloaded, err := loadCalendar(opts...); err != nil {
+				log.Printf("serve: keeping the current seasons: %v", err)
+			} else {
+				cal = reloaded
+			}
+			if reloaded, err := loadTemplates(); err != nil {
+				log.Printf("serve: keeping the current templates: %v", err)
+			} else {
+				tmpls = reloaded
+			}
+			next = clk.Now()
+		case <-timer.C:
+		}
+	}
+}
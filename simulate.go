@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/rosszurowski/small-seasons-bot/seasons"
+)
+
+// simulate writes every post that would go out to each platform between the
+// -from and -to times in args to w, stepping from one run to the next the way
+// serve would. -from defaults to now, and -to to a year after it. Accounts
+// start out with no posts, and the posts made along the way count as posted,
+// as they would on the account. Nothing is posted.
+func simulate(ctx context.Context, cal *seasons.Calendar, tmpls templates, now time.Time, args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	fromAt := fs.String("from", "", `time to start at, like "2027-02-04" or "2027-02-04 16:02" in the post time's zone (defaults to now)`)
+	toAt := fs.String("to", "", "time to stop at (defaults to a year after -from)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	loc := cal.PostTime().Location
+	from := now
+	if *fromAt != "" {
+		t, err := parseInstant(*fromAt, loc)
+		if err != nil {
+			return fmt.Errorf("parsing -from: %w", err)
+		}
+		from = t
+	}
+	to := from.AddDate(1, 0, 0)
+	if *toAt != "" {
+		t, err := parseInstant(*toAt, loc)
+		if err != nil {
+			return fmt.Errorf("parsing -to: %w", err)
+		}
+		to = t
+	}
+
+	ps, err := platforms(ctx, tmpls)
+	if err != nil {
+		return err
+	}
+	printers := make([]*printer, len(ps))
+	days := make([]int, len(ps))
+	for i, p := range ps {
+		days[i] = p.previewDays
+		printers[i] = &printer{name: p.name, w: w}
+	}
+	for at := from; at.Before(to); at = cal.NextRun(at, days...) {
+		for i, p := range ps {
+			printers[i].now = at
+			if err := p.run(ctx, printers[i], cal, at); err != nil {
+				return fmt.Errorf("%s at %s: %w", p.name, at.Format(time.RFC3339), err)
+			}
+		}
+	}
+	return nil
+}
+
+// printer is a platform's account in a simulation. It writes the posts made
+// to it to w, and keeps them, so they count as posted from then on.
+type printer struct {
+	name  string
+	w     io.Writer
+	now   time.Time // time of the run being simulated
+	posts []recentPost
+}
+
+func (a *printer) since(_ context.Context, from time.Time) ([]recentPost, error) {
+	var posts []recentPost
+	for _, post := range a.posts {
+		if !post.created.Before(from) {
+			posts = append(posts, post)
+		}
+	}
+	return posts, nil
+}
+
+func (a *printer) publish(_ context.Context, d draft) (recentPost, error) {
+	id := fmt.Sprintf("%s/%d", a.name, len(a.posts)+1)
+	post := recentPost{
+		id:      id,
+		url:     "https://example.com/" + id,
+		text:    d.fullText(),
+		markers: d.markers,
+		created: a.now,
+	}
+	a.posts = append(a.posts, post)
+	text := post.text
+	if d.quote.id != "" {
+		text += "\n[quoting " + d.quote.url + "]"
+	}
+	fmt.Fprintf(a.w, "%s %s: %s\n%s\n\n", a.now.Format("2006-01-02 15:04 MST"), a.name, d.what, text)
+	return post, nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rosszurowski/small-seasons-bot/seasons"
+)
+
+func TestSimulate(t *testing.T) {
+	for _, name := range []string{"MASTODON_BASE_URL", "BSKY_PREVIEW_DAYS", "MASTODON_PREVIEW_DAYS", "BSKY_LOCALE", "MASTODON_LOCALE"} {
+		t.Setenv(name, "")
+	}
+	postTime, err := seasons.ParsePostTime("16:02", "Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cal, err := seasons.Default(seasons.WithPostTime(postTime))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpls, err := loadTemplates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	args := []string{"-from", "2027-02-02", "-to", "2027-02-07"}
+	if err := simulate(context.Background(), cal, tmpls, time.Now(), args, &buf); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// Setsubun falls on February 3rd 2027, and risshun and its first kō on
+	// the day after.
+	var got []string
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if strings.HasPrefix(line, "2027-") {
+			got = append(got, line)
+		}
+	}
+	want := []string{
+		"2027-02-03 16:02 JST bsky: zassetsu setsubun",
+		"2027-02-03 16:02 JST mastodon: zassetsu setsubun",
+		"2027-02-04 16:02 JST bsky: sekki risshun",
+		"2027-02-04 16:02 JST bsky: ko harukaze-kori-o-toku",
+		"2027-02-04 16:02 JST mastodon: sekki risshun",
+		"2027-02-04 16:02 JST mastodon: ko harukaze-kori-o-toku",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected posts\n%s\ngot\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+	if !strings.Contains(buf.String(), "#sekki_risshun_2027") {
+		t.Errorf("expected the Mastodon post tagged with its marker, got\n%s", buf.String())
+	}
+
+	t.Run("posts words of the day the same on every platform", func(t *testing.T) {
+		*kigo = true
+		defer func() { *kigo = false }()
+		var buf bytes.Buffer
+		args := []string{"-from", "2027-02-02", "-to", "2027-02-06"}
+		if err := simulate(context.Background(), cal, tmpls, time.Now(), args, &buf); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		for _, name := range []string{"bsky", "mastodon"} {
+			head := "2027-02-05 16:02 JST " + name + ": word of the day\n"
+			i := strings.Index(buf.String(), head)
+			if i < 0 {
+				t.Fatalf("expected a word of the day on %s, got\n%s", name, buf.String())
+			}
+			post, _, _ := strings.Cut(buf.String()[i+len(head):], "\n\n2027-")
+			post = strings.TrimSpace(post)
+			if !strings.HasSuffix(post, " #"+seasons.KigoTag) {
+				t.Errorf("expected the %s post to end with #%s, got %q", name, seasons.KigoTag, post)
+			}
+		}
+	})
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/rosszurowski/small-seasons-bot/seasons"
+)
+
+// validate lints the season content, writing every problem it finds to w. It
+// renders each season, in every description variant, for each platform and
+// checks it against that platform's length limit, and checks that every season
+// has an emoji, that seasons are listed in the order they happen, and that
+// every season is translated for accounts posting in other languages. With
+// -kigo, it also checks there are enough kigo to post each day. It
+// returns an error if there were any problems.
+func validate(ctx context.Context, cal *seasons.Calendar, tmpls templates, now time.Time, w io.Writer) error {
+	ps, err := platforms(ctx, tmpls)
+	if err != nil {
+		return err
+	}
+
+	var problems []string
+	year := cal.Year(now.Year())
+	descriptions := make(map[string][]string)
+	for _, d := range cal.Definitions() {
+		descriptions[d.ID] = d.Descriptions()
+		for _, k := range d.Ko {
+			descriptions[k.ID] = k.Descriptions()
+		}
+	}
+	for _, s := range year {
+		// Check every variant of the description, not just the one used this
+		// year.
+		for i, description := range descriptions[s.ID] {
+			s.Description, s.Variant = description, i
+			name := s.ID
+			if i > 0 {
+				name = fmt.Sprintf("%s (variant %d)", s.ID, i)
+			}
+			for _, p := range ps {
+				text, langs, err := p.render(cal, []seasons.Season{s})
+				if err != nil {
+					problems = append(problems, fmt.Sprintf("%s: %s: %v", p.name, name, err))
+					continue
+				}
+				if n := p.length(text); n > p.maxLength {
+					problems = append(problems, fmt.Sprintf("%s: %s is %d characters long, over the limit of %d", p.name, name, n, p.maxLength))
+				}
+				if *bilingual && p.locale == seasons.English && s.JapaneseDescription != "" && len(langs) == 1 {
+					problems = append(problems, fmt.Sprintf("%s: %s is too long to post in both languages", p.name, name))
+				}
+			}
+		}
+	}
+	for _, d := range cal.Definitions() {
+		problems = append(problems, lintDefinition(d)...)
+		for _, k := range d.Ko {
+			problems = append(problems, lintDefinition(k)...)
+		}
+	}
+	problems = append(problems, lintOrder(cal.Definitions(), year)...)
+	if *kigo {
+		problems = append(problems, lintKigo(cal, tmpls.saijiki, now.Year())...)
+	}
+	for _, p := range ps {
+		if p.locale == seasons.English {
+			continue
+		}
+		for _, id := range p.catalog.Missing(cal.Definitions(), p.locale) {
+			problems = append(problems, fmt.Sprintf("%s: %s is missing a %s translation", p.name, id, p.locale))
+		}
+	}
+
+	for _, p := range problems {
+		fmt.Fprintln(w, p)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("found %d problems in %d seasons", len(problems), len(year))
+	}
+	fmt.Fprintf(w, "%d seasons OK\n", len(year))
+	return nil
+}
+
+func lintDefinition(d seasons.Definition) []string {
+	var problems []string
+	if d.Title == "" {
+		problems = append(problems, fmt.Sprintf("%s: missing title", d.ID))
+	}
+	if d.Description == "" {
+		problems = append(problems, fmt.Sprintf("%s: missing description", d.ID))
+	}
+	if d.Emoji == "" {
+		problems = append(problems, fmt.Sprintf("%s: missing emoji", d.ID))
+	}
+	if *bilingual {
+		if d.Japanese == "" {
+			problems = append(problems, fmt.Sprintf("%s: missing Japanese title", d.ID))
+		}
+		if d.Reading == "" {
+			problems = append(problems, fmt.Sprintf("%s: missing reading", d.ID))
+		}
+		if d.JapaneseDescription == "" {
+			problems = append(problems, fmt.Sprintf("%s: missing Japanese description", d.ID))
+		}
+	}
+	return problems
+}
+
+// lintKigo checks that each season words of the day are picked from has
+// enough kigo to post a different one every day it lasts. Calendars with
+// neither kō nor sekki have no words of the day to check.
+func lintKigo(cal *seasons.Calendar, saijiki *seasons.Saijiki, year int) []string {
+	only := cal.Only(seasons.KindKo)
+	if len(only.Year(year)) == 0 {
+		only = cal.Only(seasons.KindSekki)
+	}
+	next := only.Year(year + 1)
+	if len(only.Year(year)) == 0 || len(next) == 0 {
+		return nil
+	}
+	terms := append(only.Year(year), next[0])
+	loc := cal.PostTime().Location
+	midnight := func(t time.Time) time.Time {
+		y, m, d := t.In(loc).Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	var problems []string
+	for i, s := range terms[:len(terms)-1] {
+		days := int(midnight(terms[i+1].Start).Sub(midnight(s.Start)).Hours() / 24)
+		if n := len(saijiki.Words(s)); n < days {
+			problems = append(problems, fmt.Sprintf("%s: only %d kigo for a season of %d days", s.ID, n, days))
+		}
+	}
+	return problems
+}
+
+// lintOrder checks that seasons of each kind are listed in the order they
+// start. The list may wrap around New Year once, and each kō should follow its
+// sekki.
+func lintOrder(defs []seasons.Definition, year []seasons.Season) []string {
+	// Seasons dated from another, like nihyaku-tōka, can land in the next
+	// calendar year, so compare how far into the cycle each one starts.
+	cycle := time.Duration(365.2422 * float64(24*time.Hour))
+	var first time.Time
+	for _, s := range year {
+		if first.IsZero() || s.Start.Before(first) {
+			first = s.Start
+		}
+	}
+	starts := make(map[string]time.Duration, len(year))
+	for _, s := range year {
+		starts[s.ID] = s.Start.Sub(first) % cycle
+	}
+	// offset returns how long after from that to starts, wrapping around the
+	// year if it comes earlier.
+	offset := func(from, to string) time.Duration {
+		d := starts[to] - starts[from]
+		if d < 0 {
+			d += cycle
+		}
+		return d
+	}
+
+	// Each kind of season is listed in order separately, so split them up.
+	var kinds []seasons.Kind
+	byKind := make(map[seasons.Kind][]seasons.Definition)
+	for _, d := range defs {
+		kind := d.Kind
+		if kind == "" {
+			kind = seasons.KindSekki
+		}
+		if byKind[kind] == nil {
+			kinds = append(kinds, kind)
+		}
+		byKind[kind] = append(byKind[kind], d)
+	}
+
+	var problems []string
+	for _, kind := range kinds {
+		defs := byKind[kind]
+		// Walk the list from whichever season comes first in the year, so the
+		// wrap around New Year isn't mistaken for a problem.
+		first := 0
+		for i, d := range defs {
+			if starts[d.ID] < starts[defs[first].ID] {
+				first = i
+			}
+		}
+		for i := 1; i < len(defs); i++ {
+			prev, d := defs[(first+i-1)%len(defs)], defs[(first+i)%len(defs)]
+			if starts[d.ID] <= starts[prev.ID] {
+				problems = append(problems, fmt.Sprintf("%s: starts before %s, which is listed ahead of it", d.ID, prev.ID))
+			}
+		}
+	}
+	for _, d := range defs {
+		var last time.Duration
+		for j, k := range d.Ko {
+			off := offset(d.ID, k.ID)
+			if j > 0 && off <= last {
+				problems = append(problems, fmt.Sprintf("%s: starts before %s, which is listed ahead of it", k.ID, d.Ko[j-1].ID))
+			}
+			last = off
+		}
+	}
+	return problems
+}
+
+// translations writes which of the calendar's seasons are missing a
+// translation in each locale of catalog to w, and returns an error if any
+// locale is incomplete. Locales with nothing for the calendar are meant for
+// other calendars, so they're left out.
+func translations(cal *seasons.Calendar, catalog *seasons.Catalog, w io.Writer) error {
+	total := 0
+	for _, d := range cal.Definitions() {
+		total += 1 + len(d.Ko)
+	}
+	var incomplete []string
+	for _, locale := range catalog.Locales() {
+		missing := catalog.Missing(cal.Definitions(), locale)
+		if len(missing) == total {
+			continue
+		}
+		if len(missing) == 0 {
+			fmt.Fprintf(w, "%s: all %d seasons translated\n", locale, total)
+			continue
+		}
+		fmt.Fprintf(w, "%s: %d of %d seasons missing: %s\n", locale, len(missing), total, strings.Join(missing, ", "))
+		incomplete = append(incomplete, locale)
+	}
+	if len(incomplete) > 0 {
+		return fmt.Errorf("missing translations for %s", strings.Join(incomplete, ", "))
+	}
+	return nil
+}
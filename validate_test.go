@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/rosszurowski/small-seasons-bot/seasons"
+)
+
+func TestLintOrder(t *testing.T) {
+	for _, h := range []seasons.Hemisphere{seasons.North, seasons.South} {
+		t.Run("accepts the built-in seasons in the "+string(h), func(t *testing.T) {
+			cal, err := seasons.Default(seasons.WithHemisphere(h))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if problems := lintOrder(cal.Definitions(), cal.Year(2026)); len(problems) > 0 {
+				t.Errorf("expected no problems, got %v", problems)
+			}
+		})
+	}
+
+	t.Run("finds seasons out of order", func(t *testing.T) {
+		cal, err := seasons.Default()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defs := cal.Definitions()
+		defs[1], defs[2] = defs[2], defs[1]
+		if problems := lintOrder(defs, cal.Year(2026)); len(problems) == 0 {
+			t.Error("expected problems, got none")
+		}
+	})
+}